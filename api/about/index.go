@@ -6,6 +6,8 @@ import (
 	"github.com/vscode-contributor-website/web"
 )
 
+var handler = web.Serverless(web.AboutHandler)
+
 func Handler(w http.ResponseWriter, r *http.Request) {
-	web.AboutHandler(w, r)
+	handler(w, r)
 }
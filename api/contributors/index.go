@@ -6,6 +6,8 @@ import (
 	"github.com/vscode-contributor-website/web"
 )
 
+var handler = web.Serverless(web.ContributorsHandler)
+
 func Handler(w http.ResponseWriter, r *http.Request) {
-	web.ContributorsHandler(w, r)
+	handler(w, r)
 }
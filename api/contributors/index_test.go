@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vscode-contributor-website/scraper"
+)
+
+func TestHandlerServesCache(t *testing.T) {
+	dir := t.TempDir()
+	store := scraper.NewFileStore(dir)
+	v := scraper.Version{Major: 1, Minor: 109}
+	rel := scraper.Release{Version: v, DisplayName: "1.109", Contributors: []scraper.Contributor{
+		{GitHubUser: "cached-user", Name: "Cached User", PRs: []scraper.PR{{Repo: "o/r", Number: "1", URL: "https://github.com/o/r/pull/1"}}},
+	}}
+	if err := store.SaveVersions([]scraper.Version{v}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveRelease(rel); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SCRAPER_CACHE_DIR", dir)
+
+	w := httptest.NewRecorder()
+	Handler(w, httptest.NewRequest("GET", "/contributors", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Cached User") {
+		t.Errorf("status %d; cached contributor not served:\n%s", w.Code, w.Body.String())
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/vscode-contributor-website/web"
 )
 
+var handler = web.Serverless(web.HomeHandler)
+
 func Handler(w http.ResponseWriter, r *http.Request) {
-	web.HomeHandler(w, r)
+	handler(w, r)
 }
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/vscode-contributor-website/export"
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// runExport is the "export" subcommand: it fetches the releases in range,
+// or only reads the cache with -cached, and writes them as CSV or JSON
+// Lines.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s export [flags]\n\nWrites releases, contributors or PRs as CSV or JSON Lines.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	cfg := scraper.ConfigFromEnv()
+	cfg.RegisterFlags(fs)
+	kind := fs.String("kind", export.PRs, "rows to export: releases, contributors or prs")
+	format := fs.String("format", export.CSV, "output format: csv or jsonl")
+	from := fs.String("from", "", "oldest release to export, e.g. 1.100")
+	to := fs.String("to", "", "newest release to export, e.g. 1.109")
+	repo := fs.String("repo", "", "only PRs to this repository, as owner/name or name")
+	output := fs.String("o", "", "file to write instead of standard output")
+	cachedOnly := fs.Bool("cached", false, "export only releases already in the cache (-cache-dir); fetch nothing")
+	fs.Parse(args)
+
+	if export.Columns(*kind) == nil {
+		return fmt.Errorf("unknown -kind %q", *kind)
+	}
+	if !slices.Contains(export.Formats, *format) {
+		return fmt.Errorf("unknown -format %q", *format)
+	}
+	filter := export.Filter{Repo: *repo}
+	var err error
+	if *from != "" {
+		if filter.From, err = scraper.ParseVersion(*from); err != nil {
+			return fmt.Errorf("-from: %w", err)
+		}
+	}
+	if *to != "" {
+		if filter.To, err = scraper.ParseVersion(*to); err != nil {
+			return fmt.Errorf("-to: %w", err)
+		}
+	}
+	if err := scraper.Configure(cfg); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if !*cachedOnly {
+		if err := fetchForExport(ctx, filter); err != nil {
+			return err
+		}
+	}
+
+	if *output == "" {
+		return writeExport(os.Stdout, *kind, *format, filter)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := writeExport(f, *kind, *format, filter); err != nil {
+		f.Close()
+		return err
+	}
+	// Close reports write errors the file system deferred, e.g. a full disk.
+	return f.Close()
+}
+
+// writeExport writes the cached releases to w, buffered.
+func writeExport(w io.Writer, kind, format string, filter export.Filter) error {
+	bw := bufio.NewWriter(w)
+	if err := export.Write(bw, scraper.GetIndex(), kind, format, filter); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// fetchForExport discovers the available releases and fetches those in
+// range that are not cached yet. A failed discovery is only fatal when
+// nothing is cached.
+func fetchForExport(ctx context.Context, filter export.Filter) error {
+	if _, err := scraper.Refresh(ctx); err != nil {
+		if len(scraper.GetIndex().Releases()) == 0 {
+			return err
+		}
+		log.Printf("export: refresh failed, exporting what is cached: %v", err)
+	}
+
+	var missing []scraper.Version
+	index := scraper.GetIndex()
+	for _, v := range scraper.GetAvailableVersions() {
+		if _, ok := index.Release(v); !ok && filter.Includes(v) {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	log.Printf("export: fetching %d releases", len(missing))
+	err := scraper.RefreshVersions(ctx, missing)
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	if err != nil {
+		log.Printf("export: some releases could not be fetched: %v", err)
+	}
+	return nil
+}
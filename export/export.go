@@ -0,0 +1,360 @@
+// Package export writes the scraped contributor data as flat rows, one
+// table per kind, in CSV or JSON Lines. Columns never change order and
+// every JSON Lines row carries every column, so the output loads into
+// spreadsheets, pandas or a Parquet schema without reshaping.
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// Kinds of rows.
+const (
+	Releases     = "releases"     // one row per release
+	Contributors = "contributors" // one row per person thanked in a release, per role
+	PRs          = "prs"          // one row per pull request
+)
+
+// Formats.
+const (
+	CSV   = "csv"
+	JSONL = "jsonl"
+)
+
+// Kinds and Formats list the valid kinds and formats.
+var (
+	Kinds   = []string{Releases, Contributors, PRs}
+	Formats = []string{CSV, JSONL}
+)
+
+// Roles a contributor is thanked in, the role column.
+const (
+	RolePR            = "pr"
+	RoleIssueTracking = "issue_tracking"
+	RoleDocumentation = "documentation"
+	RoleLocalization  = "localization"
+)
+
+var columns = map[string][]string{
+	Releases: {"version", "display_name", "date", "date_month_only", "recovery",
+		"contributors", "prs", "issue_tracking", "documentation", "localization", "warnings"},
+	Contributors: {"version", "release_date", "role", "github_user", "name", "language", "prs", "first_time"},
+	PRs: {"version", "release_date", "role", "github_user", "name", "repo", "number", "title", "url",
+		"enriched", "merged_at", "labels", "additions", "deletions", "changed_files", "linked_issues"},
+}
+
+// Columns returns the columns of a kind, in order.
+func Columns(kind string) []string {
+	return columns[kind]
+}
+
+// Filter narrows an export. The zero Filter exports everything.
+type Filter struct {
+	// From and To are the oldest and newest release to include; a zero
+	// version leaves that end open.
+	From, To scraper.Version
+	// Repo, if set, keeps only pull requests to that repository, as
+	// "owner/name" or "name", and the releases and contributors that have
+	// any. Issue tracking and localization, which have no pull requests,
+	// are left out.
+	Repo string
+}
+
+// Includes reports whether the release v is in range.
+func (f Filter) Includes(v scraper.Version) bool {
+	return (f.From.IsZero() || !v.Less(f.From.Base())) && (f.To.IsZero() || !f.To.Base().Less(v))
+}
+
+func (f Filter) prs(prs []scraper.PR) []scraper.PR {
+	if f.Repo == "" {
+		return prs
+	}
+	var out []scraper.PR
+	for _, pr := range prs {
+		if pr.InRepo(f.Repo) {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// ContentType returns the MIME type of a format.
+func ContentType(format string) string {
+	if format == JSONL {
+		return "application/jsonl; charset=utf-8"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Write streams the rows of kind for the releases in index, newest release
+// first and in the order of the release notes within a release.
+func Write(w io.Writer, index *scraper.Index, kind, format string, f Filter) error {
+	cols := Columns(kind)
+	if cols == nil {
+		return fmt.Errorf("unknown kind %q; use one of %s", kind, strings.Join(Kinds, ", "))
+	}
+	var out rowWriter
+	switch format {
+	case CSV:
+		out = newCSVWriter(w, cols)
+	case JSONL:
+		out = &jsonlWriter{w: bufio.NewWriter(w), cols: cols}
+	default:
+		return fmt.Errorf("unknown format %q; use one of %s", format, strings.Join(Formats, ", "))
+	}
+
+	// first_time is relative to the exported repository when there is one
+	firstTimes := index.ForRepo(f.Repo)
+	for _, rel := range index.Releases() {
+		if !f.Includes(rel.Version) {
+			continue
+		}
+		var err error
+		switch kind {
+		case Releases:
+			err = releaseRows(out, rel, f)
+		case Contributors:
+			err = contributorRows(out, firstTimes, rel, f)
+		case PRs:
+			err = prRows(out, rel, f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return out.flush()
+}
+
+func releaseRows(out rowWriter, rel scraper.Release, f Filter) error {
+	contributors, prs := 0, 0
+	for _, c := range rel.Contributors {
+		if n := len(f.prs(c.PRs)); n > 0 || f.Repo == "" {
+			contributors++
+			prs += n
+		}
+	}
+	issueTracking, localization := len(rel.IssueTracking), len(rel.Localization)
+	documentation := 0
+	for _, c := range rel.Documentation {
+		if len(f.prs(c.PRs)) > 0 || f.Repo == "" {
+			documentation++
+		}
+	}
+	if f.Repo != "" {
+		if prs == 0 && documentation == 0 {
+			return nil
+		}
+		issueTracking, localization = 0, 0
+	}
+	return out.write(rel.Version, rel.DisplayName, date(rel.Date), rel.DateMonthOnly, version(rel.Recovery),
+		contributors, prs, issueTracking, documentation, localization, len(rel.Warnings))
+}
+
+// contributorRows writes the contributors of rel; first_time is whether
+// rel is a contributor's first release in firstTimes.
+func contributorRows(out rowWriter, firstTimes *scraper.Index, rel scraper.Release, f Filter) error {
+	day := date(rel.Date)
+	for _, c := range rel.Contributors {
+		prs := f.prs(c.PRs)
+		if len(prs) == 0 && f.Repo != "" {
+			continue
+		}
+		firstTime := firstTimes.IsFirstTime(c.GitHubUser, rel.Version)
+		if err := out.write(rel.Version, day, RolePR, c.GitHubUser, c.Name, nil, len(prs), firstTime); err != nil {
+			return err
+		}
+	}
+	for _, c := range rel.Documentation {
+		prs := f.prs(c.PRs)
+		if len(prs) == 0 && f.Repo != "" {
+			continue
+		}
+		if err := out.write(rel.Version, day, RoleDocumentation, c.GitHubUser, c.Name, nil, len(prs), false); err != nil {
+			return err
+		}
+	}
+	if f.Repo != "" {
+		return nil
+	}
+	for _, c := range rel.IssueTracking {
+		if err := out.write(rel.Version, day, RoleIssueTracking, c.GitHubUser, c.Name, nil, 0, false); err != nil {
+			return err
+		}
+	}
+	for _, t := range rel.Localization {
+		if err := out.write(rel.Version, day, RoleLocalization, optional(t.GitHubUser), t.Name, t.Language, 0, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func prRows(out rowWriter, rel scraper.Release, f Filter) error {
+	day := date(rel.Date)
+	for _, group := range []struct {
+		role         string
+		contributors []scraper.Contributor
+	}{{RolePR, rel.Contributors}, {RoleDocumentation, rel.Documentation}} {
+		for _, c := range group.contributors {
+			for _, pr := range f.prs(c.PRs) {
+				var mergedAt interface{}
+				if !pr.MergedAt.IsZero() {
+					mergedAt = pr.MergedAt.UTC().Format(time.RFC3339)
+				}
+				err := out.write(rel.Version, day, group.role, c.GitHubUser, c.Name, pr.Repo, pr.Number, pr.Title, pr.URL,
+					pr.Enriched, mergedAt, list(pr.Labels), pr.Additions, pr.Deletions, pr.ChangedFiles, list(pr.LinkedIssues))
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// date is a release date as "2006-01-02", or null.
+func date(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.Format("2006-01-02")
+}
+
+// version is a version, or null if it is zero.
+func version(v scraper.Version) interface{} {
+	if v.IsZero() {
+		return nil
+	}
+	return v.String()
+}
+
+// optional is s, or null if it is empty.
+func optional(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// list is never null, so the column keeps its type.
+func list(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+// rowWriter writes rows of values in column order. Values are strings,
+// ints, bools, []string, scraper.Version or nil.
+type rowWriter interface {
+	write(values ...interface{}) error
+	flush() error
+}
+
+// flushEvery is how many rows are buffered before they are written out.
+const flushEvery = 500
+
+type csvWriter struct {
+	w      *csv.Writer
+	header []string
+	rows   int
+}
+
+func newCSVWriter(w io.Writer, cols []string) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w), header: cols}
+}
+
+// csvCell guards a text cell against formula injection: spreadsheets run a
+// cell starting with =, +, -, @, a tab or a carriage return as a formula, so
+// such cells get a leading apostrophe, which they display as text.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (c *csvWriter) write(values ...interface{}) error {
+	if c.header != nil {
+		if err := c.w.Write(c.header); err != nil {
+			return err
+		}
+		c.header = nil
+	}
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			record[i] = csvCell(v)
+		case int:
+			record[i] = strconv.Itoa(v)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		case []string:
+			record[i] = csvCell(strings.Join(v, ";"))
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	if c.rows++; c.rows%flushEvery == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) flush() error {
+	if c.header != nil {
+		// No rows; the header still describes the empty table
+		if err := c.w.Write(c.header); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	w    *bufio.Writer
+	cols []string
+	buf  bytes.Buffer
+}
+
+func (j *jsonlWriter) write(values ...interface{}) error {
+	j.buf.Reset()
+	j.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			j.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(j.cols[i])
+		j.buf.Write(key)
+		j.buf.WriteByte(':')
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		j.buf.Write(b)
+	}
+	j.buf.WriteString("}\n")
+	_, err := j.w.Write(j.buf.Bytes())
+	return err
+}
+
+func (j *jsonlWriter) flush() error {
+	return j.w.Flush()
+}
@@ -0,0 +1,120 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vscode-contributor-website/scraper"
+)
+
+func testIndex() *scraper.Index {
+	pr := func(repo, n string) scraper.PR {
+		return scraper.PR{Repo: repo, Number: n, Title: "PR " + n, URL: "https://github.com/" + repo + "/pull/" + n}
+	}
+	releases := []scraper.Release{
+		{Version: scraper.Version{Major: 1, Minor: 2}, DisplayName: "1.2", Contributors: []scraper.Contributor{
+			{GitHubUser: "alice", Name: "Alice", PRs: []scraper.PR{pr("microsoft/vscode", "1"), pr("microsoft/vscode-python", "2")}},
+			{GitHubUser: "bob", Name: "Bob, Jr.", PRs: []scraper.PR{pr("microsoft/vscode", "3")}},
+		}, Localization: []scraper.Translator{{Name: "Zoé", Language: "French"}}},
+		{Version: scraper.Version{Major: 1, Minor: 1}, DisplayName: "1.1", Contributors: []scraper.Contributor{
+			{GitHubUser: "alice", Name: "Alice", PRs: []scraper.PR{pr("microsoft/vscode", "4")}},
+		}},
+	}
+	return scraper.NewIndex(releases)
+}
+
+func TestWriteCSV(t *testing.T) {
+	for _, kind := range Kinds {
+		var b bytes.Buffer
+		if err := Write(&b, testIndex(), kind, CSV, Filter{}); err != nil {
+			t.Fatal(err)
+		}
+		records, err := csv.NewReader(&b).ReadAll()
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		if strings.Join(records[0], ",") != strings.Join(Columns(kind), ",") {
+			t.Errorf("%s header = %v", kind, records[0])
+		}
+		for _, r := range records[1:] {
+			if len(r) != len(Columns(kind)) {
+				t.Errorf("%s row %v has %d columns, want %d", kind, r, len(r), len(Columns(kind)))
+			}
+		}
+	}
+}
+
+func TestWriteFilters(t *testing.T) {
+	var b bytes.Buffer
+	f := Filter{From: scraper.Version{Major: 1, Minor: 2}, Repo: "vscode-python"}
+	if err := Write(&b, testIndex(), PRs, JSONL, f); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d rows, want 1:\n%s", len(lines), b.String())
+	}
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &row); err != nil {
+		t.Fatal(err)
+	}
+	if row["number"] != "2" || row["version"] != "1.2" || row["merged_at"] != nil || len(row) != len(Columns(PRs)) {
+		t.Errorf("row = %v", row)
+	}
+
+	b.Reset()
+	if err := Write(&b, testIndex(), Contributors, CSV, Filter{To: scraper.Version{Major: 1, Minor: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	want := `version,release_date,role,github_user,name,language,prs,first_time
+1.2,,pr,alice,Alice,,2,false
+1.2,,pr,bob,"Bob, Jr.",,1,true
+1.2,,localization,,Zoé,French,0,false
+1.1,,pr,alice,Alice,,1,true
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteFirstTimeInRepo(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, testIndex(), Contributors, CSV, Filter{Repo: "vscode-python"}); err != nil {
+		t.Fatal(err)
+	}
+	// alice contributed to vscode in 1.1, but to vscode-python first in 1.2
+	want := `version,release_date,role,github_user,name,language,prs,first_time
+1.2,,pr,alice,Alice,,1,true
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteCSVFormulas(t *testing.T) {
+	index := scraper.NewIndex([]scraper.Release{{Version: scraper.Version{Major: 1, Minor: 3}, DisplayName: "1.3", Localization: []scraper.Translator{
+		{Name: "=HYPERLINK(\"http://x\")", Language: "+1"},
+		{Name: "-2", Language: "@SUM(A1)"},
+		{Name: "\tTab", Language: "\rReturn"},
+		{Name: "Plain = text", Language: "French"},
+	}}})
+	var b bytes.Buffer
+	if err := Write(&b, index, Contributors, CSV, Filter{}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range records[1:] {
+		got = append(got, r[4]+"|"+r[5])
+	}
+	want := []string{`'=HYPERLINK("http://x")|'+1`, "'-2|'@SUM(A1)", "'\tTab|'\rReturn", "Plain = text|French"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("cells = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,708 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is the result of a request. Data is nil when the request was
+// rejected before it ran: it did not parse, did not match the schema or was
+// over the limits.
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error is an error in a Response.
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"` // response keys and list indexes
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func errorAt(loc Location, format string, args ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{loc}}
+}
+
+// defaultListSize is the number of items the complexity limit assumes a
+// list field returns when neither the query nor the field say otherwise.
+const defaultListSize = 10
+
+// maxFields bounds how many fields validation visits, counting each time a
+// fragment is spread, so a small query cannot expand into an enormous one.
+const maxFields = 10000
+
+// Execute runs a query against the schema.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		se := err.(*SyntaxError)
+		return &Response{Errors: []*Error{errorAt(se.Loc, "%s", se.Message)}}
+	}
+	op, gqlErr := selectOperation(doc, req.OperationName)
+	if gqlErr != nil {
+		return &Response{Errors: []*Error{gqlErr}}
+	}
+	vars, gqlErr := coerceVariables(op, req.Variables)
+	if gqlErr != nil {
+		return &Response{Errors: []*Error{gqlErr}}
+	}
+
+	e := &executor{ctx: ctx, schema: s, doc: doc, vars: vars}
+	if err := e.checkFragmentCycles(); err != nil {
+		return &Response{Errors: []*Error{err}}
+	}
+	e.maxCost = math.MaxInt
+	if s.MaxComplexity > 0 {
+		e.maxCost = s.MaxComplexity + 1
+	}
+	root := s.root()
+	cost, depth := e.validate(root, op.Selections, 1)
+	if len(e.errors) > 0 {
+		return &Response{Errors: e.errors}
+	}
+	if s.MaxDepth > 0 && depth > s.MaxDepth {
+		return &Response{Errors: []*Error{errorAt(op.Loc, "query is nested %d levels deep, more than the limit of %d", depth, s.MaxDepth)}}
+	}
+	if s.MaxComplexity > 0 && cost > s.MaxComplexity {
+		return &Response{Errors: []*Error{errorAt(op.Loc,
+			"query complexity is over the limit of %d; select fewer fields or pass smaller \"first\" arguments", s.MaxComplexity)}}
+	}
+
+	data, _ := e.executeSelections(root, nil, op.Selections, nil)
+	resp := &Response{Data: data, Errors: e.errors}
+	if data == nil {
+		resp.Data = json.RawMessage("null")
+	}
+	return resp
+}
+
+func selectOperation(doc *Document, name string) (*Operation, *Error) {
+	var op *Operation
+	switch {
+	case name != "":
+		for _, o := range doc.Operations {
+			if o.Name == name {
+				op = o
+			}
+		}
+		if op == nil {
+			return nil, &Error{Message: fmt.Sprintf("no operation named %q", name)}
+		}
+	case len(doc.Operations) == 1:
+		op = doc.Operations[0]
+	default:
+		return nil, &Error{Message: "the document has several operations; choose one with operationName"}
+	}
+	if op.Kind != "query" {
+		return nil, errorAt(op.Loc, "only queries are supported, not %ss", op.Kind)
+	}
+	return op, nil
+}
+
+// coerceVariables checks the given variables against the operation's
+// declarations and applies defaults.
+func coerceVariables(op *Operation, given map[string]interface{}) (map[string]interface{}, *Error) {
+	vars := make(map[string]interface{}, len(op.Vars))
+	for _, def := range op.Vars {
+		t, err := parseInputType(def.Type)
+		if err != nil {
+			return nil, errorAt(def.Loc, "variable $%s: %v", def.Name, err)
+		}
+		v, ok := given[def.Name]
+		if !ok {
+			v, ok = def.Default, def.Default != nil
+		}
+		if !ok {
+			if _, nonNull := t.(*NonNull); nonNull {
+				return nil, errorAt(def.Loc, "variable $%s of type %s is required", def.Name, def.Type)
+			}
+			continue
+		}
+		c, err := coerceInput(t, v, nil)
+		if err != nil {
+			return nil, errorAt(def.Loc, "variable $%s: %v", def.Name, err)
+		}
+		vars[def.Name] = c
+	}
+	return vars, nil
+}
+
+// parseInputType resolves a variable type such as "[String!]"; only the
+// built-in scalars are input types.
+func parseInputType(s string) (Type, error) {
+	if inner, ok := strings.CutSuffix(s, "!"); ok {
+		t, err := parseInputType(inner)
+		if err != nil {
+			return nil, err
+		}
+		return NewNonNull(t), nil
+	}
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		t, err := parseInputType(s[1 : len(s)-1])
+		if err != nil {
+			return nil, err
+		}
+		return NewList(t), nil
+	}
+	for _, scalar := range []*Scalar{Int, Float, String, Boolean, ID} {
+		if scalar.Name == s {
+			return scalar, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown input type %s", s)
+}
+
+// coerceInput converts a literal or variable value to t. vars is nil when
+// coercing the variables themselves.
+func coerceInput(t Type, v interface{}, vars map[string]interface{}) (interface{}, error) {
+	if name, ok := v.(Variable); ok {
+		if vars == nil {
+			return nil, fmt.Errorf("variables are not allowed here")
+		}
+		var set bool
+		if v, set = vars[string(name)]; !set {
+			if _, nonNull := t.(*NonNull); nonNull {
+				return nil, fmt.Errorf("variable $%s is not set", name)
+			}
+			return nil, nil
+		}
+		// Already coerced to its declared type
+		return v, nil
+	}
+	switch t := t.(type) {
+	case *NonNull:
+		if v == nil {
+			return nil, fmt.Errorf("expected a %s, found null", t.Of)
+		}
+		return coerceInput(t.Of, v, vars)
+	case *List:
+		if v == nil {
+			return nil, nil
+		}
+		items, ok := v.([]interface{})
+		if !ok {
+			// A single value stands for a list of one
+			items = []interface{}{v}
+		}
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			c, err := coerceInput(t.Of, item, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = c
+		}
+		return out, nil
+	case *Scalar:
+		if v == nil {
+			return nil, nil
+		}
+		return t.Coerce(v)
+	}
+	return nil, fmt.Errorf("%s is not an input type", t)
+}
+
+type executor struct {
+	ctx     context.Context
+	schema  *Schema
+	doc     *Document
+	vars    map[string]interface{}
+	errors  []*Error
+	fields  int // visited by validate
+	maxCost int // where validate stops counting
+}
+
+func (e *executor) fail(err *Error) {
+	e.errors = append(e.errors, err)
+}
+
+// checkFragmentCycles rejects fragments that spread themselves, directly
+// or through other fragments.
+func (e *executor) checkFragmentCycles() *Error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var visit func(sels []Selection) *Error
+	visit = func(sels []Selection) *Error {
+		for _, sel := range sels {
+			switch sel := sel.(type) {
+			case *FieldNode:
+				if err := visit(sel.Selections); err != nil {
+					return err
+				}
+			case *InlineFragment:
+				if err := visit(sel.Selections); err != nil {
+					return err
+				}
+			case *FragmentSpread:
+				f := e.doc.Fragments[sel.Name]
+				if f == nil {
+					continue // reported by validate
+				}
+				switch state[f.Name] {
+				case visiting:
+					return errorAt(sel.Loc, "fragment %q spreads itself", f.Name)
+				case done:
+					continue
+				}
+				state[f.Name] = visiting
+				if err := visit(f.Selections); err != nil {
+					return err
+				}
+				state[f.Name] = done
+			}
+		}
+		return nil
+	}
+	for _, f := range e.doc.Fragments {
+		if state[f.Name] == 0 {
+			state[f.Name] = visiting
+			if err := visit(f.Selections); err != nil {
+				return err
+			}
+			state[f.Name] = done
+		}
+	}
+	return nil
+}
+
+// fieldGroup is the fields of a selection set that share a response key.
+type fieldGroup struct {
+	key   string
+	nodes []*FieldNode
+}
+
+// collectFields flattens fragments and applies @skip and @include, merging
+// fields selected more than once under the same response key.
+func (e *executor) collectFields(obj *Object, sels []Selection, groups []*fieldGroup, visited map[string]bool) ([]*fieldGroup, *Error) {
+	for _, sel := range sels {
+		var directives []*Directive
+		switch sel := sel.(type) {
+		case *FieldNode:
+			directives = sel.Directives
+		case *FragmentSpread:
+			directives = sel.Directives
+		case *InlineFragment:
+			directives = sel.Directives
+		}
+		include, err := e.included(directives)
+		if err != nil {
+			return nil, err
+		}
+		if !include {
+			continue
+		}
+
+		switch sel := sel.(type) {
+		case *FieldNode:
+			var group *fieldGroup
+			for _, g := range groups {
+				if g.key == sel.Alias {
+					group = g
+				}
+			}
+			if group == nil {
+				group = &fieldGroup{key: sel.Alias}
+				groups = append(groups, group)
+			} else if group.nodes[0].Name != sel.Name {
+				return nil, errorAt(sel.Loc, "%q selects both %s and %s", sel.Alias, group.nodes[0].Name, sel.Name)
+			}
+			group.nodes = append(group.nodes, sel)
+		case *FragmentSpread:
+			f := e.doc.Fragments[sel.Name]
+			if f == nil {
+				return nil, errorAt(sel.Loc, "unknown fragment %q", sel.Name)
+			}
+			if visited[sel.Name] {
+				continue
+			}
+			visited[sel.Name] = true
+			if f.TypeCond != obj.Name {
+				return nil, errorAt(sel.Loc, "fragment %q on %s cannot be spread in %s", f.Name, f.TypeCond, obj.Name)
+			}
+			if groups, err = e.collectFields(obj, f.Selections, groups, visited); err != nil {
+				return nil, err
+			}
+		case *InlineFragment:
+			if sel.TypeCond != "" && sel.TypeCond != obj.Name {
+				return nil, errorAt(sel.Loc, "fragment on %s cannot be spread in %s", sel.TypeCond, obj.Name)
+			}
+			if groups, err = e.collectFields(obj, sel.Selections, groups, visited); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return groups, nil
+}
+
+// included evaluates @skip and @include.
+func (e *executor) included(directives []*Directive) (bool, *Error) {
+	for _, d := range directives {
+		if d.Name != "skip" && d.Name != "include" {
+			return false, errorAt(d.Loc, "unknown directive @%s", d.Name)
+		}
+		if len(d.Args) != 1 || d.Args[0].Name != "if" {
+			return false, errorAt(d.Loc, "@%s takes a single \"if\" argument", d.Name)
+		}
+		v, err := coerceInput(NewNonNull(Boolean), d.Args[0].Value, e.vars)
+		if err != nil {
+			return false, errorAt(d.Loc, "@%s: %v", d.Name, err)
+		}
+		if v.(bool) == (d.Name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// subselections merges the selection sets of fields sharing a key.
+func subselections(nodes []*FieldNode) []Selection {
+	if len(nodes) == 1 {
+		return nodes[0].Selections
+	}
+	var sels []Selection
+	for _, n := range nodes {
+		sels = append(sels, n.Selections...)
+	}
+	return sels
+}
+
+// coerceArgs checks a field's arguments and applies defaults.
+func (e *executor) coerceArgs(f *Field, node *FieldNode) (map[string]interface{}, *Error) {
+	args := make(map[string]interface{}, len(f.Args))
+	for _, a := range node.Args {
+		known := false
+		for _, def := range f.Args {
+			known = known || def.Name == a.Name
+		}
+		if !known {
+			return nil, errorAt(a.Loc, "field %s has no argument %q", f.Name, a.Name)
+		}
+	}
+	for _, def := range f.Args {
+		var given *Argument
+		for _, a := range node.Args {
+			if a.Name == def.Name {
+				given = a
+			}
+		}
+		if given == nil {
+			if def.Default != nil {
+				args[def.Name] = def.Default
+			} else if _, nonNull := def.Type.(*NonNull); nonNull {
+				return nil, errorAt(node.Loc, "field %s requires the argument %q", f.Name, def.Name)
+			}
+			continue
+		}
+		v, err := coerceInput(def.Type, given.Value, e.vars)
+		if err != nil {
+			return nil, errorAt(given.Loc, "argument %q of %s: %v", def.Name, f.Name, err)
+		}
+		if v == nil && def.Default != nil {
+			v = def.Default
+		}
+		args[def.Name] = v
+	}
+	return args, nil
+}
+
+// namedType strips List and NonNull.
+func namedType(t Type) Type {
+	for {
+		switch w := t.(type) {
+		case *List:
+			t = w.Of
+		case *NonNull:
+			t = w.Of
+		default:
+			return t
+		}
+	}
+}
+
+func isList(t Type) bool {
+	if n, ok := t.(*NonNull); ok {
+		t = n.Of
+	}
+	_, ok := t.(*List)
+	return ok
+}
+
+// validate checks a selection set against obj and returns its estimated
+// cost and how many levels of fields it nests, counting from depth.
+func (e *executor) validate(obj *Object, sels []Selection, depth int) (cost, maxDepth int) {
+	groups, err := e.collectFields(obj, sels, nil, map[string]bool{})
+	if err != nil {
+		e.fail(err)
+		return 0, depth
+	}
+	maxDepth = depth
+	if e.schema.MaxDepth > 0 && depth > e.schema.MaxDepth {
+		return 0, depth // too deep already; no need to look further
+	}
+	for _, g := range groups {
+		node := g.nodes[0]
+		if e.fields++; e.fields > maxFields {
+			if e.fields == maxFields+1 {
+				e.fail(errorAt(node.Loc, "query selects more than %d fields", maxFields))
+			}
+			return cost, maxDepth
+		}
+		if node.Name == "__typename" {
+			cost = e.addCost(cost, 1)
+			continue
+		}
+		f := obj.Field(node.Name)
+		if f == nil {
+			e.fail(errorAt(node.Loc, "type %s has no field %q", obj.Name, node.Name))
+			continue
+		}
+		args, err := e.coerceArgs(f, node)
+		if err != nil {
+			e.fail(err)
+			continue
+		}
+
+		child, isObject := namedType(f.Type).(*Object)
+		sub := subselections(g.nodes)
+		switch {
+		case isObject && len(sub) == 0:
+			e.fail(errorAt(node.Loc, "field %s of type %s needs a selection of subfields", node.Name, f.Type))
+			continue
+		case !isObject && len(sub) > 0:
+			e.fail(errorAt(node.Loc, "field %s of type %s has no subfields", node.Name, f.Type))
+			continue
+		case !isObject:
+			cost = e.addCost(cost, 1)
+			continue
+		}
+
+		childDepth := depth + 1
+		if f.unnested {
+			childDepth = depth
+		}
+		childCost, childDepth := e.validate(child, sub, childDepth)
+		if isList(f.Type) {
+			n := f.ListSize
+			if n == 0 {
+				n = defaultListSize
+			}
+			if first, ok := args["first"].(int); ok && first >= 0 {
+				if e.schema.MaxFirst > 0 && first > e.schema.MaxFirst {
+					e.fail(errorAt(node.Loc, "argument \"first\" of field %s must be at most %d", node.Name, e.schema.MaxFirst))
+					continue
+				}
+				n = first
+			}
+			childCost = e.mulCost(childCost, n)
+		}
+		cost = e.addCost(cost, e.addCost(1, childCost))
+		maxDepth = max(maxDepth, childDepth)
+	}
+	return cost, maxDepth
+}
+
+// addCost and mulCost saturate at maxCost, so costs stop growing once they
+// are over the limit and a huge "first" cannot overflow them.
+func (e *executor) addCost(a, b int) int {
+	if a > e.maxCost-b {
+		return e.maxCost
+	}
+	return a + b
+}
+
+func (e *executor) mulCost(a, n int) int {
+	if n != 0 && a > e.maxCost/n {
+		return e.maxCost
+	}
+	return a * n
+}
+
+// executeSelections resolves a selection set that has been validated. It
+// returns false if a non-null field could not be resolved, making source
+// null.
+func (e *executor) executeSelections(obj *Object, source interface{}, sels []Selection, path []interface{}) (*orderedMap, bool) {
+	groups, _ := e.collectFields(obj, sels, nil, map[string]bool{})
+	out := &orderedMap{}
+	for _, g := range groups {
+		node := g.nodes[0]
+		fieldPath := append(path[:len(path):len(path)], g.key)
+		if node.Name == "__typename" {
+			out.set(g.key, obj.Name)
+			continue
+		}
+		f := obj.Field(node.Name)
+		v, ok := e.executeField(f, source, node, subselections(g.nodes), fieldPath)
+		if !ok {
+			return nil, false
+		}
+		out.set(g.key, v)
+	}
+	return out, true
+}
+
+func (e *executor) executeField(f *Field, source interface{}, node *FieldNode, sub []Selection, path []interface{}) (interface{}, bool) {
+	if err := e.ctx.Err(); err != nil {
+		return e.fieldError(f.Type, node, path, err)
+	}
+	args, gqlErr := e.coerceArgs(f, node)
+	if gqlErr != nil {
+		return e.fieldError(f.Type, node, path, gqlErr)
+	}
+	var v interface{}
+	var err error
+	if f.Resolve != nil {
+		v, err = f.Resolve(Params{Context: e.ctx, Source: source, Args: args})
+	} else {
+		v, err = defaultResolve(source, f.Name)
+	}
+	if err != nil {
+		return e.fieldError(f.Type, node, path, err)
+	}
+	return e.complete(f.Type, node, v, sub, path)
+}
+
+// fieldError records err and returns null, or false if t may not be null.
+func (e *executor) fieldError(t Type, node *FieldNode, path []interface{}, err error) (interface{}, bool) {
+	e.fail(&Error{Message: err.Error(), Locations: []Location{node.Loc}, Path: path})
+	_, nonNull := t.(*NonNull)
+	return nil, !nonNull
+}
+
+// complete converts a resolved value to t. It returns false if an error
+// made a non-null value null, which makes the enclosing value null too.
+func (e *executor) complete(t Type, node *FieldNode, v interface{}, sub []Selection, path []interface{}) (interface{}, bool) {
+	if n, ok := t.(*NonNull); ok {
+		c, ok := e.completeNullable(n.Of, node, v, sub, path)
+		if !ok {
+			return nil, false
+		}
+		if c == nil {
+			e.fail(&Error{Message: node.Alias + " cannot be null", Locations: []Location{node.Loc}, Path: path})
+			return nil, false
+		}
+		return c, true
+	}
+	c, ok := e.completeNullable(t, node, v, sub, path)
+	if !ok {
+		return nil, true
+	}
+	return c, true
+}
+
+// completeNullable is complete for a type that is not NonNull. It returns
+// false if the value must be null because of an error in a non-null item
+// or field within it.
+func (e *executor) completeNullable(t Type, node *FieldNode, v interface{}, sub []Selection, path []interface{}) (interface{}, bool) {
+	if isNull(v) {
+		return nil, true
+	}
+	switch t := t.(type) {
+	case *List:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			e.fail(&Error{Message: fmt.Sprintf("%s resolved to %T, not a list", node.Alias, v), Locations: []Location{node.Loc}, Path: path})
+			return nil, false
+		}
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			c, ok := e.complete(t.Of, node, rv.Index(i).Interface(), sub, append(path[:len(path):len(path)], i))
+			if !ok {
+				return nil, false
+			}
+			items[i] = c
+		}
+		return items, true
+	case *Object:
+		m, ok := e.executeSelections(t, v, sub, path)
+		if !ok {
+			return nil, false
+		}
+		return m, true
+	}
+	return v, true
+}
+
+// isNull reports whether a resolved value is null: nil, a nil pointer or
+// map, or a value whose IsZero method reports true, such as a zero
+// time.Time. Nil slices are empty lists, not null.
+func isNull(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	if z, ok := v.(interface{ IsZero() bool }); ok {
+		return z.IsZero()
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// defaultResolve returns the struct field of source whose name matches the
+// GraphQL field name ignoring case, so "githubUser" resolves GitHubUser.
+func defaultResolve(source interface{}, name string) (interface{}, error) {
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		if fv := rv.FieldByNameFunc(func(n string) bool { return strings.EqualFold(n, name) }); fv.IsValid() {
+			return fv.Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("no resolver for field %s", name)
+}
+
+// orderedMap is a JSON object that keeps its keys in the order the query
+// selected them.
+type orderedMap struct {
+	keys   []string
+	values []interface{}
+}
+
+func (m *orderedMap) set(key string, v interface{}) {
+	m.keys = append(m.keys, key)
+	m.values = append(m.values, v)
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		b.Write(key)
+		b.WriteByte(':')
+		v, err := json.Marshal(m.values[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
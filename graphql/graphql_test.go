@@ -0,0 +1,250 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testItem struct {
+	Name  string
+	Count int
+	Tags  []string
+}
+
+func testSchema() *Schema {
+	items := []testItem{{Name: "a", Count: 1, Tags: []string{"x"}}, {Name: "b", Count: 2}}
+	item := &Object{Name: "Item"}
+	item.Fields = []*Field{
+		{Name: "name", Type: NewNonNull(String)},
+		{Name: "count", Type: NewNonNull(Int)},
+		{Name: "tags", Type: NewNonNull(NewList(NewNonNull(String)))},
+		{Name: "self", Type: item, Resolve: func(p Params) (interface{}, error) { return p.Source, nil }},
+		{Name: "broken", Type: NewNonNull(String), Resolve: func(p Params) (interface{}, error) {
+			return nil, errors.New("broken")
+		}},
+		{
+			Name: "siblings", Type: NewList(item),
+			Args:    []*Arg{{Name: "first", Type: Int}},
+			Resolve: func(p Params) (interface{}, error) { return items, nil },
+		},
+	}
+	query := &Object{Name: "Query", Fields: []*Field{
+		{
+			Name: "items", Type: NewNonNull(NewList(NewNonNull(item))), ListSize: 100,
+			Args: []*Arg{{Name: "first", Type: Int}},
+			Resolve: func(p Params) (interface{}, error) {
+				if n, ok := p.Args["first"].(int); ok && n < len(items) {
+					return items[:n], nil
+				}
+				return items, nil
+			},
+		},
+		{
+			Name: "item", Type: item,
+			Args: []*Arg{{Name: "name", Type: NewNonNull(String)}, {Name: "upper", Type: Boolean, Default: false}},
+			Resolve: func(p Params) (interface{}, error) {
+				for _, it := range items {
+					if it.Name == p.Args["name"] {
+						if p.Args["upper"].(bool) {
+							it.Name = strings.ToUpper(it.Name)
+						}
+						return &it, nil
+					}
+				}
+				return nil, nil
+			},
+		},
+	}}
+	return &Schema{Query: query, MaxDepth: 4, MaxComplexity: 500}
+}
+
+func run(t *testing.T, query string, vars map[string]interface{}) (string, []*Error) {
+	t.Helper()
+	resp := testSchema().Execute(context.Background(), Request{Query: query, Variables: vars})
+	if resp.Data == nil {
+		return "", resp.Errors
+	}
+	b, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b), resp.Errors
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		query string
+		vars  map[string]interface{}
+		want  string
+	}{
+		{`{ items { name count } }`, nil, `{"items":[{"name":"a","count":1},{"name":"b","count":2}]}`},
+		{`{ first: items(first: 1) { n: name, tags } __typename }`, nil, `{"first":[{"n":"a","tags":["x"]}],"__typename":"Query"}`},
+		{`query Q($n: String!, $up: Boolean) { item(name: $n, upper: $up) { name } }`,
+			map[string]interface{}{"n": "b", "up": true}, `{"item":{"name":"B"}}`},
+		{`query($n: String = "a") { item(name: $n) { ...F self { count } } } fragment F on Item { name self { name } }`,
+			nil, `{"item":{"name":"a","self":{"name":"a","count":1}}}`},
+		{`{ items(first: 1) { name @skip(if: true) ... @include(if: false) { count } tags } }`, nil, `{"items":[{"tags":["x"]}]}`},
+		{`{ item(name: "zzz") { name } }`, nil, `{"item":null}`},
+		{`query($f: Int) { items(first: $f) { name } }`, map[string]interface{}{"f": float64(1)}, `{"items":[{"name":"a"}]}`},
+	}
+	for _, tt := range tests {
+		got, errs := run(t, tt.query, tt.vars)
+		if len(errs) > 0 || got != tt.want {
+			t.Errorf("%s\n got %s %v\nwant %s", tt.query, got, errs, tt.want)
+		}
+	}
+}
+
+func TestExecuteNullPropagation(t *testing.T) {
+	got, errs := run(t, `{ item(name: "a") { name broken } }`, nil)
+	if got != `{"item":null}` || len(errs) != 1 || errs[0].Message != "broken" {
+		t.Errorf("got %s %v", got, errs)
+	}
+	if path, _ := json.Marshal(errs[0].Path); string(path) != `["item","broken"]` {
+		t.Errorf("error path %s", path)
+	}
+}
+
+func TestExecuteRejects(t *testing.T) {
+	tests := []struct{ query, want string }{
+		{`{ items { name `, "expected"},
+		{`{ nope }`, `no field "nope"`},
+		{`{ items }`, "needs a selection"},
+		{`{ items { name { x } } }`, "has no subfields"},
+		{`{ item { name } }`, `requires the argument "name"`},
+		{`{ item(name: 1) { name } }`, "expected a String"},
+		{`{ items(bogus: 1) { name } }`, `no argument "bogus"`},
+		{`{ items { ...F } } fragment F on Item { ...G } fragment G on Item { ...F }`, "spreads itself"},
+		{`{ items { ...F } } fragment F on Query { items { name } }`, "cannot be spread"},
+		{`{ items { x: name x: count } }`, "selects both"},
+		{`mutation { items { name } }`, "only queries"},
+		{`{ item(name: "a") { self { self { self { self { name } } } } } }`, "levels deep"},
+		{`{ items { name tags count self { name } } }`, "complexity"},
+		{`query($n: String!) { item(name: $n) { name } }`, "is required"},
+		{`{ items { name @bogus } }`, "unknown directive"},
+	}
+	for _, tt := range tests {
+		got, errs := run(t, tt.query, nil)
+		if got != "" || len(errs) == 0 || !strings.Contains(errs[0].Message, tt.want) {
+			t.Errorf("%s: got %s %v, want an error containing %q", tt.query, got, errs, tt.want)
+		}
+	}
+	if _, errs := run(t, `{ items(first: 2) { name tags count self { name } } }`, nil); len(errs) > 0 {
+		t.Errorf("a smaller first is still rejected: %v", errs)
+	}
+}
+
+func TestExecuteHugeFirst(t *testing.T) {
+	// 2147483647 cubed overflows an int64 cost unless counting saturates
+	query := `{ items(first: 2147483647) { siblings(first: 2147483647) { siblings(first: 2147483647) { name } } } }`
+	if got, errs := run(t, query, nil); got != "" || len(errs) != 1 || !strings.Contains(errs[0].Message, "complexity") {
+		t.Errorf("got %s %v, want the complexity limit", got, errs)
+	}
+
+	s := testSchema()
+	s.MaxFirst = 50
+	resp := s.Execute(context.Background(), Request{Query: query})
+	if resp.Data != nil || len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, "at most 50") {
+		t.Errorf("got %v, want first rejected", resp.Errors)
+	}
+	if resp := s.Execute(context.Background(), Request{Query: `{ items(first: 50) { name } }`}); len(resp.Errors) > 0 {
+		t.Errorf("first: 50 rejected: %v", resp.Errors)
+	}
+}
+
+func TestSDL(t *testing.T) {
+	sdl := testSchema().SDL()
+	for _, want := range []string{
+		"schema {\n  query: Query\n}",
+		"items(first: Int): [Item!]!",
+		"item(name: String!, upper: Boolean = false): Item",
+		"type Item {\n  name: String!",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL lacks %q:\n%s", want, sdl)
+		}
+	}
+}
+
+// IntrospectionQuery is the query GraphQL clients such as GraphiQL send to
+// learn a schema, as graphql-js getIntrospectionQuery writes it.
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) {
+    name description args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+func TestIntrospection(t *testing.T) {
+	tests := []struct{ query, want string }{
+		{`{ __type(name: "Item") { kind name fields { name } } }`,
+			`{"__type":{"kind":"OBJECT","name":"Item","fields":[{"name":"name"},{"name":"count"},{"name":"tags"},{"name":"self"},{"name":"broken"},{"name":"siblings"}]}}`},
+		{`{ __type(name: "Nope") { name } }`, `{"__type":null}`},
+		{`{ __type(name: "Query") { fields { name args { name defaultValue } } } }`,
+			`{"__type":{"fields":[{"name":"items","args":[{"name":"first","defaultValue":null}]},` +
+				`{"name":"item","args":[{"name":"name","defaultValue":null},{"name":"upper","defaultValue":"false"}]}]}}`},
+		{`{ __type(name: "Item") { fields(includeDeprecated: true) { type { kind ofType { kind ofType { name } } } } } }`,
+			`{"__type":{"fields":[{"type":{"kind":"NON_NULL","ofType":{"kind":"SCALAR","ofType":null}}},` +
+				`{"type":{"kind":"NON_NULL","ofType":{"kind":"SCALAR","ofType":null}}},` +
+				`{"type":{"kind":"NON_NULL","ofType":{"kind":"LIST","ofType":{"name":null}}}},` +
+				`{"type":{"kind":"OBJECT","ofType":null}},` +
+				`{"type":{"kind":"NON_NULL","ofType":{"kind":"SCALAR","ofType":null}}},` +
+				`{"type":{"kind":"LIST","ofType":{"kind":"OBJECT","ofType":null}}}]}}`},
+		{`{ __schema { queryType { name } mutationType { name } types { name } directives { name } } }`,
+			`{"__schema":{"queryType":{"name":"Query"},"mutationType":null,"types":[{"name":"Boolean"},{"name":"Int"},{"name":"Item"},{"name":"Query"},{"name":"String"},` +
+				`{"name":"__Directive"},{"name":"__EnumValue"},{"name":"__Field"},{"name":"__InputValue"},{"name":"__Schema"},{"name":"__Type"}],` +
+				`"directives":[{"name":"skip"},{"name":"include"}]}}`},
+	}
+	for _, tt := range tests {
+		got, errs := run(t, tt.query, nil)
+		if len(errs) > 0 || got != tt.want {
+			t.Errorf("%s\n got %s %v\nwant %s", tt.query, got, errs, tt.want)
+		}
+	}
+
+	// A client's full introspection query: ofType chains do not count
+	// towards the depth limit, and the cost assumes the schema's sizes.
+	s := testSchema()
+	s.MaxDepth, s.MaxComplexity = 6, 5000
+	resp := s.Execute(context.Background(), Request{Query: IntrospectionQuery})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("introspection query: %v", resp.Errors)
+	}
+	b, _ := json.Marshal(resp.Data)
+	if !strings.Contains(string(b), `{"name":"tags","description":null,"args":[],"type":{"kind":"NON_NULL","name":null,"ofType":{"kind":"LIST","name":null,"ofType":{"kind":"NON_NULL","name":null,"ofType":{"kind":"SCALAR","name":"String","ofType":null}}}}`) {
+		t.Errorf("introspection of Item.tags missing from %s", b)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(`query Q($a: [Int!] = [1, 2]) { a: f(x: {y: "z\u00e9", w: [$a]}) @skip(if: false) { ...F ... on T { g } } } fragment F on T { h }`)
+	f.Add(`{ f(s: """block "" string""", n: -1.5e3, e: ENUM, b: null) }`)
+	f.Fuzz(func(t *testing.T, query string) {
+		doc, err := Parse(query)
+		if err == nil && len(doc.Operations) == 0 {
+			t.Errorf("Parse(%q) returned no operations and no error", query)
+		}
+		testSchema().Execute(context.Background(), Request{Query: query})
+	})
+}
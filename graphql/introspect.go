@@ -0,0 +1,281 @@
+package graphql
+
+import "sort"
+
+// Introspection: the __schema and __type fields every query root has, and
+// the __Schema, __Type, __Field, __InputValue, __EnumValue and __Directive
+// types they return, as the GraphQL specification defines them. Schemas
+// here have only scalars and objects, so interfaces, possibleTypes,
+// enumValues and inputFields are always empty or null, and nothing is
+// deprecated. Enum values such as a type's kind are returned as strings.
+
+// directive describes a directive the executor supports.
+type directive struct {
+	Name        string
+	Description string
+	Locations   []string
+	Args        []*Arg
+}
+
+var directives = []*directive{
+	{
+		Name:        "skip",
+		Description: "Directs the executor to skip this field or fragment when the `if` argument is true.",
+		Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:        []*Arg{{Name: "if", Description: "Skipped when true.", Type: NewNonNull(Boolean)}},
+	},
+	{
+		Name:        "include",
+		Description: "Directs the executor to include this field or fragment only when the `if` argument is true.",
+		Locations:   []string{"FIELD", "FRAGMENT_SPREAD", "INLINE_FRAGMENT"},
+		Args:        []*Arg{{Name: "if", Description: "Included when true.", Type: NewNonNull(Boolean)}},
+	},
+}
+
+// introspection holds what a Schema needs to answer introspection queries.
+type introspection struct {
+	root   *Object         // the query root plus __schema and __type
+	types  []Type          // every named type, by name
+	byName map[string]Type // types by name
+	schema *introspectedSchema
+}
+
+// introspectedSchema is the value __schema resolves to.
+type introspectedSchema struct {
+	schema *Schema
+	types  []Type
+}
+
+// introspect builds s's introspection types and query root.
+func (s *Schema) introspect() *introspection {
+	in := &introspection{byName: make(map[string]Type)}
+	schemaType, typeType := introspectionTypes()
+	var visit func(t Type)
+	visit = func(t Type) {
+		t = namedType(t)
+		name := t.String()
+		if _, ok := in.byName[name]; ok {
+			return
+		}
+		in.byName[name] = t
+		in.types = append(in.types, t)
+		if o, ok := t.(*Object); ok {
+			for _, f := range o.Fields {
+				for _, a := range f.Args {
+					visit(a.Type)
+				}
+				visit(f.Type)
+			}
+		}
+	}
+	visit(s.Query)
+	visit(schemaType)
+	for _, d := range directives {
+		for _, a := range d.Args {
+			visit(a.Type)
+		}
+	}
+	sort.Slice(in.types, func(i, j int) bool { return in.types[i].String() < in.types[j].String() })
+
+	// The usual introspection query of a GraphQL client lists every
+	// type's fields and their arguments, so the complexity limit assumes
+	// this schema's sizes: its types, and the fields per object and
+	// arguments per field on average.
+	var objects, fields, args int
+	for _, t := range in.types {
+		if o, ok := t.(*Object); ok {
+			objects++
+			fields += len(o.Fields)
+			for _, f := range o.Fields {
+				args += len(f.Args)
+			}
+		}
+	}
+	fieldsField := typeType.Field("fields")
+	schemaType.Field("types").ListSize = len(in.types)
+	fieldsField.ListSize = max(1, (fields+objects-1)/objects)
+	namedType(fieldsField.Type).(*Object).Field("args").ListSize = max(1, (args+fields-1)/max(fields, 1))
+	in.schema = &introspectedSchema{schema: s, types: in.types}
+
+	in.root = &Object{Name: s.Query.Name, Description: s.Query.Description, Fields: append(s.Query.Fields[:len(s.Query.Fields):len(s.Query.Fields)],
+		&Field{
+			Name: "__schema", Type: NewNonNull(schemaType), Description: "Access the current type schema of this server.",
+			Resolve: func(p Params) (interface{}, error) { return in.schema, nil },
+		},
+		&Field{
+			Name: "__type", Type: typeType, Description: "Request the type information of a single type.",
+			Args: []*Arg{{Name: "name", Type: NewNonNull(String)}},
+			Resolve: func(p Params) (interface{}, error) {
+				if t, ok := in.byName[p.Args["name"].(string)]; ok {
+					return t, nil
+				}
+				return nil, nil
+			},
+		},
+	)}
+	return in
+}
+
+// introspectionTypes returns new __Schema and __Type objects, and with them
+// the other introspection types.
+func introspectionTypes() (schemaType, typeType *Object) {
+	schemaType = &Object{Name: "__Schema", Description: "A GraphQL Schema defines the capabilities of a GraphQL server."}
+	typeType = &Object{Name: "__Type", Description: "The fundamental unit of any GraphQL Schema is the type."}
+	fieldType := &Object{Name: "__Field", Description: "Object and Interface types are described by a list of Fields, each of which has a name, potentially a list of arguments, and a return type."}
+	inputValueType := &Object{Name: "__InputValue", Description: "Arguments provided to Fields or Directives and the input fields of an InputObject are represented as Input Values which describe their type and optionally a default value."}
+	enumValueType := &Object{Name: "__EnumValue", Description: "One possible value for a given Enum."}
+	directiveType := &Object{Name: "__Directive", Description: "A Directive provides a way to describe alternate runtime execution and type validation behavior in a GraphQL document."}
+
+	nonNullList := func(t Type) Type { return NewNonNull(NewList(NewNonNull(t))) }
+	includeDeprecated := []*Arg{{Name: "includeDeprecated", Type: Boolean, Default: false}}
+	null := func(p Params) (interface{}, error) { return nil, nil }
+	isFalse := func(p Params) (interface{}, error) { return false, nil }
+
+	schemaType.Fields = []*Field{
+		{Name: "description", Type: String, Resolve: null},
+		{
+			Name: "types", Type: nonNullList(typeType), Description: "A list of all types supported by this server.",
+			Resolve: func(p Params) (interface{}, error) { return p.Source.(*introspectedSchema).types, nil },
+		},
+		{
+			Name: "queryType", Type: NewNonNull(typeType), Description: "The type that query operations will be rooted at.",
+			Resolve: func(p Params) (interface{}, error) { return p.Source.(*introspectedSchema).schema.Query, nil },
+		},
+		{Name: "mutationType", Type: typeType, Description: "Always null: mutations are not supported.", Resolve: null},
+		{Name: "subscriptionType", Type: typeType, Description: "Always null: subscriptions are not supported.", Resolve: null},
+		{
+			Name: "directives", Type: nonNullList(directiveType), Description: "A list of all directives supported by this server.", ListSize: len(directives),
+			Resolve: func(p Params) (interface{}, error) { return directives, nil },
+		},
+	}
+
+	typeType.Fields = []*Field{
+		{
+			Name: "kind", Type: NewNonNull(String),
+			Resolve: func(p Params) (interface{}, error) {
+				switch p.Source.(type) {
+				case *Scalar:
+					return "SCALAR", nil
+				case *Object:
+					return "OBJECT", nil
+				case *List:
+					return "LIST", nil
+				}
+				return "NON_NULL", nil
+			},
+		},
+		{
+			Name: "name", Type: String,
+			Resolve: func(p Params) (interface{}, error) {
+				switch t := p.Source.(type) {
+				case *Scalar:
+					return t.Name, nil
+				case *Object:
+					return t.Name, nil
+				}
+				return nil, nil
+			},
+		},
+		{
+			Name: "description", Type: String,
+			Resolve: func(p Params) (interface{}, error) {
+				if o, ok := p.Source.(*Object); ok && o.Description != "" {
+					return o.Description, nil
+				}
+				return nil, nil
+			},
+		},
+		{Name: "specifiedByURL", Type: String, Resolve: null},
+		{
+			Name: "fields", Type: NewList(NewNonNull(fieldType)), ListSize: 1, Args: includeDeprecated,
+			Resolve: func(p Params) (interface{}, error) {
+				if o, ok := p.Source.(*Object); ok {
+					return o.Fields, nil
+				}
+				return nil, nil
+			},
+		},
+		{
+			Name: "interfaces", Type: NewList(NewNonNull(typeType)), ListSize: 1,
+			Resolve: func(p Params) (interface{}, error) {
+				if _, ok := p.Source.(*Object); ok {
+					return []Type{}, nil
+				}
+				return nil, nil
+			},
+		},
+		{Name: "possibleTypes", Type: NewList(NewNonNull(typeType)), ListSize: 1, Resolve: null},
+		{Name: "enumValues", Type: NewList(NewNonNull(enumValueType)), ListSize: 1, Args: includeDeprecated, Resolve: null},
+		{Name: "inputFields", Type: NewList(NewNonNull(inputValueType)), ListSize: 1, Args: includeDeprecated, Resolve: null},
+		{
+			Name: "ofType", Type: typeType, unnested: true,
+			Resolve: func(p Params) (interface{}, error) {
+				switch t := p.Source.(type) {
+				case *List:
+					return t.Of, nil
+				case *NonNull:
+					return t.Of, nil
+				}
+				return nil, nil
+			},
+		},
+		{Name: "isOneOf", Type: Boolean, Resolve: null},
+	}
+
+	argsField := func(args func(source interface{}) []*Arg) *Field {
+		return &Field{
+			Name: "args", Type: nonNullList(inputValueType), ListSize: 1, Args: includeDeprecated,
+			Resolve: func(p Params) (interface{}, error) { return args(p.Source), nil },
+		}
+	}
+	fieldType.Fields = []*Field{
+		{Name: "name", Type: NewNonNull(String)},
+		{Name: "description", Type: String, Resolve: func(p Params) (interface{}, error) { return optional(p.Source.(*Field).Description), nil }},
+		argsField(func(source interface{}) []*Arg { return source.(*Field).Args }),
+		{Name: "type", Type: NewNonNull(typeType)},
+		{Name: "isDeprecated", Type: NewNonNull(Boolean), Resolve: isFalse},
+		{Name: "deprecationReason", Type: String, Resolve: null},
+	}
+
+	inputValueType.Fields = []*Field{
+		{Name: "name", Type: NewNonNull(String)},
+		{Name: "description", Type: String, Resolve: func(p Params) (interface{}, error) { return optional(p.Source.(*Arg).Description), nil }},
+		{Name: "type", Type: NewNonNull(typeType)},
+		{
+			Name: "defaultValue", Type: String, Description: "A GraphQL-formatted string representing the default value for this input value.",
+			Resolve: func(p Params) (interface{}, error) {
+				if d := p.Source.(*Arg).Default; d != nil {
+					return formatValue(d), nil
+				}
+				return nil, nil
+			},
+		},
+		{Name: "isDeprecated", Type: NewNonNull(Boolean), Resolve: isFalse},
+		{Name: "deprecationReason", Type: String, Resolve: null},
+	}
+
+	enumValueType.Fields = []*Field{
+		{Name: "name", Type: NewNonNull(String)},
+		{Name: "description", Type: String, Resolve: null},
+		{Name: "isDeprecated", Type: NewNonNull(Boolean), Resolve: isFalse},
+		{Name: "deprecationReason", Type: String, Resolve: null},
+	}
+
+	directiveType.Fields = []*Field{
+		{Name: "name", Type: NewNonNull(String)},
+		{Name: "description", Type: String},
+		{Name: "locations", Type: nonNullList(String), ListSize: 3},
+		argsField(func(source interface{}) []*Arg { return source.(*directive).Args }),
+		{Name: "isRepeatable", Type: NewNonNull(Boolean), Resolve: isFalse},
+	}
+
+	return schemaType, typeType
+}
+
+// optional returns s, or nil if it is empty, for nullable strings.
+func optional(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,642 @@
+// Package graphql is a small GraphQL implementation: enough of the query
+// language to run read-only queries against a schema of Go resolvers, with
+// limits on how deep and how expensive a query may be. It supports fields,
+// aliases, arguments, variables, fragments, the @skip and @include
+// directives and introspection (__schema, __type and __typename), so
+// GraphiQL and client code generators can load the schema as from any
+// server; mutations, subscriptions, interfaces, unions, enums and input
+// objects are not supported.
+//
+// It is hand-written rather than built on graphql-go or gqlgen so the
+// module keeps no third-party dependencies: the site and its Vercel
+// functions build from go.mod alone, with nothing to vendor or download in
+// the serverless build. The limits are also its own: graphql-go has no
+// query cost limit, and gqlgen generates code from a schema file, where
+// this schema is assembled from the scraper's types in web/graphql.go. It
+// is deliberately limited to what a read-only API needs. The schema is
+// also available as SDL (see Schema.SDL).
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Location is a 1-based line and column in a query.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Document is a parsed query.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query in a Document.
+type Operation struct {
+	Kind       string // "query", "mutation" or "subscription"
+	Name       string
+	Vars       []*VarDef
+	Selections []Selection
+	Loc        Location
+}
+
+// VarDef declares a variable of an operation.
+type VarDef struct {
+	Name    string
+	Type    string // as written, e.g. "[String!]!"
+	Default interface{}
+	Loc     Location
+}
+
+// Selection is a *FieldNode, *FragmentSpread or *InlineFragment.
+type Selection interface {
+	location() Location
+}
+
+// FieldNode is a field selected in a query.
+type FieldNode struct {
+	Alias      string // the response key; the name if there is no alias
+	Name       string
+	Args       []*Argument
+	Directives []*Directive
+	Selections []Selection
+	Loc        Location
+}
+
+// FragmentSpread is "...Name".
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Loc        Location
+}
+
+// InlineFragment is "... on Type { ... }"; TypeCond may be empty.
+type InlineFragment struct {
+	TypeCond   string
+	Directives []*Directive
+	Selections []Selection
+	Loc        Location
+}
+
+// Fragment is a named fragment definition.
+type Fragment struct {
+	Name       string
+	TypeCond   string
+	Selections []Selection
+	Loc        Location
+}
+
+// Argument is a field or directive argument. Its value is a string, int,
+// float64, bool, nil, Enum, Variable, []interface{} or
+// map[string]interface{} of those.
+type Argument struct {
+	Name  string
+	Value interface{}
+	Loc   Location
+}
+
+// Directive is "@name(args)".
+type Directive struct {
+	Name string
+	Args []*Argument
+	Loc  Location
+}
+
+// Variable is a "$name" reference in a value.
+type Variable string
+
+// Enum is an unquoted enum value.
+type Enum string
+
+func (f *FieldNode) location() Location      { return f.Loc }
+func (f *FragmentSpread) location() Location { return f.Loc }
+func (f *InlineFragment) location() Location { return f.Loc }
+
+// SyntaxError is a query that does not parse.
+type SyntaxError struct {
+	Message string
+	Loc     Location
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at %d:%d: %s", e.Loc.Line, e.Loc.Column, e.Message)
+}
+
+// maxNesting bounds how deeply selections and values may nest while
+// parsing, so a hostile query cannot exhaust the stack before the depth
+// limit is checked.
+const maxNesting = 64
+
+// Parse parses a query document.
+func Parse(query string) (doc *Document, err error) {
+	p := &parser{lex: lexer{src: query, line: 1, lineStart: 0}}
+	defer func() {
+		if r := recover(); r != nil {
+			se, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, se
+		}
+	}()
+	p.next()
+	return p.document(), nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	text string // the punctuator, name, number or unescaped string
+	loc  Location
+}
+
+type lexer struct {
+	src       string
+	pos       int
+	line      int
+	lineStart int
+}
+
+func (l *lexer) loc() Location {
+	return Location{Line: l.line, Column: utf8.RuneCountInString(l.src[l.lineStart:l.pos]) + 1}
+}
+
+func (l *lexer) fail(loc Location, format string, args ...interface{}) {
+	panic(&SyntaxError{Message: fmt.Sprintf(format, args...), Loc: loc})
+}
+
+func (l *lexer) newline() {
+	l.line++
+	l.lineStart = l.pos
+}
+
+// skip passes over whitespace, commas and comments.
+func (l *lexer) skip() {
+	for l.pos < len(l.src) {
+		switch c := l.src[l.pos]; c {
+		case ' ', '\t', ',', '\r':
+			l.pos++
+		case '\n':
+			l.pos++
+			l.newline()
+		case '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		default:
+			if strings.HasPrefix(l.src[l.pos:], "\ufeff") {
+				l.pos += 3
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (l *lexer) next() token {
+	l.skip()
+	loc := l.loc()
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, loc: loc}
+	}
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokPunct, text: "...", loc: loc}
+	case strings.IndexByte("!$():=@[]{}|&", c) >= 0:
+		l.pos++
+		return token{kind: tokPunct, text: string(c), loc: loc}
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokName, text: l.src[start:l.pos], loc: loc}
+	case c == '-' || isDigit(c):
+		return l.number(loc)
+	case c == '"':
+		if strings.HasPrefix(l.src[l.pos:], `"""`) {
+			return l.blockString(loc)
+		}
+		return l.string(loc)
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.fail(loc, "unexpected character %q", r)
+	return token{}
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+func (l *lexer) number(loc Location) token {
+	start := l.pos
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	digits := func() int {
+		n := 0
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+			n++
+		}
+		return n
+	}
+	if digits() == 0 {
+		l.fail(loc, "invalid number")
+	}
+	kind := tokInt
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		l.pos++
+		kind = tokFloat
+		if digits() == 0 {
+			l.fail(loc, "invalid number")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		l.pos++
+		kind = tokFloat
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if digits() == 0 {
+			l.fail(loc, "invalid number")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || l.src[l.pos] == '.' || isLetter(l.src[l.pos])) {
+		l.fail(loc, "invalid number")
+	}
+	return token{kind: kind, text: l.src[start:l.pos], loc: loc}
+}
+
+func (l *lexer) string(loc Location) token {
+	l.pos++ // opening quote
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' {
+			l.fail(loc, "unterminated string")
+		}
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokString, text: b.String(), loc: loc}
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				l.fail(loc, "unterminated string")
+			}
+			esc := l.src[l.pos+1]
+			l.pos += 2
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					l.fail(loc, "invalid unicode escape")
+				}
+				n, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					l.fail(loc, "invalid unicode escape")
+				}
+				b.WriteRune(rune(n))
+				l.pos += 4
+			default:
+				l.fail(loc, "invalid escape \\%c", esc)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+}
+
+// blockString reads a """ string. Unlike the specification it does not
+// strip common indentation, only the leading and trailing blank lines.
+func (l *lexer) blockString(loc Location) token {
+	l.pos += 3
+	var b strings.Builder
+	for {
+		if l.pos >= len(l.src) {
+			l.fail(loc, "unterminated string")
+		}
+		switch {
+		case strings.HasPrefix(l.src[l.pos:], `"""`):
+			l.pos += 3
+			return token{kind: tokString, text: strings.Trim(b.String(), "\n"), loc: loc}
+		case strings.HasPrefix(l.src[l.pos:], `\"""`):
+			b.WriteString(`"""`)
+			l.pos += 4
+		default:
+			c := l.src[l.pos]
+			b.WriteByte(c)
+			l.pos++
+			if c == '\n' {
+				l.newline()
+			}
+		}
+	}
+}
+
+type parser struct {
+	lex     lexer
+	tok     token
+	nesting int
+}
+
+func (p *parser) next() {
+	p.tok = p.lex.next()
+}
+
+func (p *parser) fail(format string, args ...interface{}) {
+	p.lex.fail(p.tok.loc, format, args...)
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokPunct && p.tok.text == punct
+}
+
+// skipPunct consumes punct if it is next.
+func (p *parser) skipPunct(punct string) bool {
+	if p.peek(punct) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) {
+	if !p.skipPunct(punct) {
+		p.fail("expected %q, found %s", punct, p.describe())
+	}
+}
+
+func (p *parser) name() string {
+	if p.tok.kind != tokName {
+		p.fail("expected a name, found %s", p.describe())
+	}
+	name := p.tok.text
+	p.next()
+	return name
+}
+
+func (p *parser) describe() string {
+	if p.tok.kind == tokEOF {
+		return "end of query"
+	}
+	return strconv.Quote(p.tok.text)
+}
+
+func (p *parser) enter() {
+	p.nesting++
+	if p.nesting > maxNesting {
+		p.fail("query nested too deeply")
+	}
+}
+
+func (p *parser) leave() {
+	p.nesting--
+}
+
+func (p *parser) document() *Document {
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.tok.kind != tokEOF {
+		loc := p.tok.loc
+		switch {
+		case p.peek("{"):
+			doc.Operations = append(doc.Operations, &Operation{Kind: "query", Selections: p.selectionSet(), Loc: loc})
+		case p.tok.kind == tokName && p.tok.text == "fragment":
+			p.next()
+			f := &Fragment{Loc: loc}
+			if f.Name = p.name(); f.Name == "on" {
+				p.lex.fail(loc, "a fragment cannot be named \"on\"")
+			}
+			if p.tok.kind != tokName || p.tok.text != "on" {
+				p.fail("expected \"on\", found %s", p.describe())
+			}
+			p.next()
+			f.TypeCond = p.name()
+			p.directives() // accepted and ignored on definitions
+			f.Selections = p.selectionSet()
+			if _, dup := doc.Fragments[f.Name]; dup {
+				p.lex.fail(loc, "fragment %q is defined more than once", f.Name)
+			}
+			doc.Fragments[f.Name] = f
+		case p.tok.kind == tokName && (p.tok.text == "query" || p.tok.text == "mutation" || p.tok.text == "subscription"):
+			op := &Operation{Kind: p.tok.text, Loc: loc}
+			p.next()
+			if p.tok.kind == tokName {
+				op.Name = p.name()
+			}
+			if p.skipPunct("(") {
+				for !p.skipPunct(")") {
+					op.Vars = append(op.Vars, p.varDef())
+				}
+			}
+			p.directives()
+			op.Selections = p.selectionSet()
+			doc.Operations = append(doc.Operations, op)
+		default:
+			p.fail("expected a query or fragment, found %s", p.describe())
+		}
+	}
+	if len(doc.Operations) == 0 {
+		p.fail("the document has no operations")
+	}
+	return doc
+}
+
+func (p *parser) varDef() *VarDef {
+	v := &VarDef{Loc: p.tok.loc}
+	p.expect("$")
+	v.Name = p.name()
+	p.expect(":")
+	v.Type = p.typeRef()
+	if p.skipPunct("=") {
+		v.Default = p.value(true)
+	}
+	p.directives()
+	return v
+}
+
+func (p *parser) typeRef() string {
+	var t string
+	if p.skipPunct("[") {
+		p.enter()
+		t = "[" + p.typeRef() + "]"
+		p.leave()
+		p.expect("]")
+	} else {
+		t = p.name()
+	}
+	if p.skipPunct("!") {
+		t += "!"
+	}
+	return t
+}
+
+func (p *parser) selectionSet() []Selection {
+	p.enter()
+	defer p.leave()
+	p.expect("{")
+	var sels []Selection
+	for !p.skipPunct("}") {
+		sels = append(sels, p.selection())
+	}
+	if len(sels) == 0 {
+		p.fail("empty selection set")
+	}
+	return sels
+}
+
+func (p *parser) selection() Selection {
+	loc := p.tok.loc
+	if p.skipPunct("...") {
+		if p.tok.kind == tokName && p.tok.text != "on" {
+			return &FragmentSpread{Name: p.name(), Directives: p.directives(), Loc: loc}
+		}
+		f := &InlineFragment{Loc: loc}
+		if p.tok.kind == tokName {
+			p.next() // "on"
+			f.TypeCond = p.name()
+		}
+		f.Directives = p.directives()
+		f.Selections = p.selectionSet()
+		return f
+	}
+
+	f := &FieldNode{Loc: loc}
+	f.Name = p.name()
+	f.Alias = f.Name
+	if p.skipPunct(":") {
+		f.Name = p.name()
+	}
+	f.Args = p.arguments(false)
+	f.Directives = p.directives()
+	if p.peek("{") {
+		f.Selections = p.selectionSet()
+	}
+	return f
+}
+
+func (p *parser) arguments(constant bool) []*Argument {
+	if !p.skipPunct("(") {
+		return nil
+	}
+	var args []*Argument
+	for !p.skipPunct(")") {
+		a := &Argument{Loc: p.tok.loc}
+		a.Name = p.name()
+		for _, prev := range args {
+			if prev.Name == a.Name {
+				p.lex.fail(a.Loc, "argument %q is given more than once", a.Name)
+			}
+		}
+		p.expect(":")
+		a.Value = p.value(constant)
+		args = append(args, a)
+	}
+	return args
+}
+
+func (p *parser) directives() []*Directive {
+	var ds []*Directive
+	for p.peek("@") {
+		d := &Directive{Loc: p.tok.loc}
+		p.next()
+		d.Name = p.name()
+		d.Args = p.arguments(false)
+		ds = append(ds, d)
+	}
+	return ds
+}
+
+// value parses a value; constant values, such as variable defaults, may
+// not refer to variables.
+func (p *parser) value(constant bool) interface{} {
+	tok := p.tok
+	switch tok.kind {
+	case tokInt:
+		p.next()
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			p.lex.fail(tok.loc, "integer %s out of range", tok.text)
+		}
+		return n
+	case tokFloat:
+		p.next()
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			p.lex.fail(tok.loc, "float %s out of range", tok.text)
+		}
+		return f
+	case tokString:
+		p.next()
+		return tok.text
+	case tokName:
+		p.next()
+		switch tok.text {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return Enum(tok.text)
+	}
+
+	p.enter()
+	defer p.leave()
+	switch {
+	case p.skipPunct("$"):
+		if constant {
+			p.lex.fail(tok.loc, "variables are not allowed here")
+		}
+		return Variable(p.name())
+	case p.skipPunct("["):
+		list := []interface{}{}
+		for !p.skipPunct("]") {
+			list = append(list, p.value(constant))
+		}
+		return list
+	case p.skipPunct("{"):
+		obj := map[string]interface{}{}
+		for !p.skipPunct("}") {
+			loc := p.tok.loc
+			name := p.name()
+			if _, dup := obj[name]; dup {
+				p.lex.fail(loc, "field %q is given more than once", name)
+			}
+			p.expect(":")
+			obj[name] = p.value(constant)
+		}
+		return obj
+	}
+	p.fail("expected a value, found %s", p.describe())
+	return nil
+}
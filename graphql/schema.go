@@ -0,0 +1,267 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Type is a *Scalar, *Object, *List or *NonNull.
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Resolvers return the Go value directly and it is
+// encoded as JSON as it is.
+type Scalar struct {
+	Name string
+	// Coerce converts an argument or variable value, as parsed or decoded
+	// from JSON, to the Go value resolvers receive.
+	Coerce func(v interface{}) (interface{}, error)
+}
+
+// The built-in scalars.
+var (
+	Int = &Scalar{Name: "Int", Coerce: func(v interface{}) (interface{}, error) {
+		switch n := v.(type) {
+		case int:
+			return n, nil
+		case float64: // JSON variables
+			if n == float64(int(n)) && n >= -1<<31 && n < 1<<31 {
+				return int(n), nil
+			}
+		}
+		return nil, fmt.Errorf("expected an Int, found %s", describe(v))
+	}}
+	Float = &Scalar{Name: "Float", Coerce: func(v interface{}) (interface{}, error) {
+		switch n := v.(type) {
+		case int:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		}
+		return nil, fmt.Errorf("expected a Float, found %s", describe(v))
+	}}
+	String = &Scalar{Name: "String", Coerce: func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("expected a String, found %s", describe(v))
+	}}
+	Boolean = &Scalar{Name: "Boolean", Coerce: func(v interface{}) (interface{}, error) {
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+		return nil, fmt.Errorf("expected a Boolean, found %s", describe(v))
+	}}
+	ID = &Scalar{Name: "ID", Coerce: func(v interface{}) (interface{}, error) {
+		switch id := v.(type) {
+		case string:
+			return id, nil
+		case int:
+			return fmt.Sprint(id), nil
+		}
+		return nil, fmt.Errorf("expected an ID, found %s", describe(v))
+	}}
+)
+
+func (s *Scalar) String() string { return s.Name }
+
+// Object is a type with fields.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+}
+
+func (o *Object) String() string { return o.Name }
+
+// Field returns o's field called name, or nil.
+func (o *Object) Field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// List is a list of another type.
+type List struct {
+	Of Type
+}
+
+func (l *List) String() string { return "[" + l.Of.String() + "]" }
+
+// NonNull is a type whose values are never null.
+type NonNull struct {
+	Of Type
+}
+
+func (n *NonNull) String() string { return n.Of.String() + "!" }
+
+// NewList returns [t].
+func NewList(t Type) *List { return &List{Of: t} }
+
+// NewNonNull returns t!.
+func NewNonNull(t Type) *NonNull { return &NonNull{Of: t} }
+
+// Field is a field of an Object.
+type Field struct {
+	Name        string
+	Description string
+	Type        Type
+	Args        []*Arg
+	Resolve     func(p Params) (interface{}, error)
+
+	// ListSize is the number of items the complexity limit assumes a list
+	// field returns when the query does not ask for fewer with a "first"
+	// argument. It defaults to 10.
+	ListSize int
+
+	// unnested fields do not count towards Schema.MaxDepth; set on
+	// __Type.ofType, whose chains end after a few List and NonNull.
+	unnested bool
+}
+
+// Arg is an argument of a Field.
+type Arg struct {
+	Name        string
+	Description string
+	Type        Type
+	Default     interface{} // used when the argument is absent; nil for none
+}
+
+// Params is what a resolver is called with.
+type Params struct {
+	Context context.Context
+	Source  interface{}            // the value of the enclosing object
+	Args    map[string]interface{} // coerced arguments, with defaults applied
+}
+
+// Schema is the entry point of queries and the limits they must stay
+// within.
+type Schema struct {
+	Query *Object
+
+	// MaxDepth is how many levels of fields a query may nest; 0 means no
+	// limit.
+	MaxDepth int
+
+	// MaxComplexity bounds the estimated cost of a query: every field
+	// costs 1, and the fields selected below a list count once for each
+	// item it is expected to return. 0 means no limit.
+	MaxComplexity int
+
+	// MaxFirst is the largest "first" argument a list field accepts; 0
+	// means no limit.
+	MaxFirst int
+
+	introspectOnce sync.Once
+	introspection  *introspection
+}
+
+// root returns the query root with the introspection fields added.
+func (s *Schema) root() *Object {
+	s.introspectOnce.Do(func() { s.introspection = s.introspect() })
+	return s.introspection.root
+}
+
+// SDL describes the schema in the GraphQL schema definition language.
+func (s *Schema) SDL() string {
+	var objects []*Object
+	seen := make(map[*Object]bool)
+	var visit func(t Type)
+	visit = func(t Type) {
+		switch t := t.(type) {
+		case *List:
+			visit(t.Of)
+		case *NonNull:
+			visit(t.Of)
+		case *Object:
+			if seen[t] {
+				return
+			}
+			seen[t] = true
+			objects = append(objects, t)
+			for _, f := range t.Fields {
+				visit(f.Type)
+			}
+		}
+	}
+	visit(s.Query)
+
+	var b strings.Builder
+	b.WriteString("schema {\n  query: " + s.Query.Name + "\n}\n")
+	for _, o := range objects {
+		b.WriteString("\n")
+		writeDescription(&b, "", o.Description)
+		b.WriteString("type " + o.Name + " {\n")
+		for _, f := range o.Fields {
+			writeDescription(&b, "  ", f.Description)
+			b.WriteString("  " + f.Name)
+			if len(f.Args) > 0 {
+				var args []string
+				for _, a := range f.Args {
+					arg := a.Name + ": " + a.Type.String()
+					if a.Default != nil {
+						arg += " = " + formatValue(a.Default)
+					}
+					args = append(args, arg)
+				}
+				b.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			b.WriteString(": " + f.Type.String() + "\n")
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, indent, desc string) {
+	if desc != "" {
+		b.WriteString(indent + "\"" + strings.ReplaceAll(desc, "\"", "\\\"") + "\"\n")
+	}
+}
+
+// formatValue writes a Go argument value as a GraphQL literal.
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			items = append(items, formatValue(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var fields []string
+		for _, k := range keys {
+			fields = append(fields, k+": "+formatValue(v[k]))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	}
+	return fmt.Sprint(v)
+}
+
+func describe(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case Enum:
+		return string(v)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return formatValue(v)
+}
@@ -1,17 +1,46 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/vscode-contributor-website/copilotapi"
 	"github.com/vscode-contributor-website/scraper"
 	"github.com/vscode-contributor-website/web"
 )
 
+// shutdownTimeout bounds how long in-flight requests and the background
+// scraper get to finish after a shutdown signal.
+const shutdownTimeout = 30 * time.Second
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg := scraper.ConfigFromEnv()
+	cfg.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	if err := scraper.Configure(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	// Stop on SIGINT or SIGTERM, e.g. when the container is replaced
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Start background contributor scraping
-	scraper.StartBackground()
+	runner := scraper.StartBackground(ctx)
 
 	fs := http.FileServer(http.Dir("public/static"))
 	http.Handle("/static/", http.StripPrefix("/static/", fs))
@@ -27,15 +56,47 @@ func main() {
 	http.HandleFunc("/ask", web.AskHandler)
 	http.HandleFunc("/contributors", web.ContributorsHandler)
 	http.HandleFunc("/leaderboard", web.LeaderboardHandler)
+	http.HandleFunc("/compare", web.CompareHandler)
 	http.HandleFunc("/api/kudos/", web.KudosHandler)
 	http.HandleFunc("/api/celebrate/", web.CelebrateHandler)
 	http.HandleFunc("/api/milestone/", web.CheckMilestone)
 	http.HandleFunc("/api/ask", copilotapi.AskHandler)
 	http.HandleFunc("/contributor/", web.ContributorProfileHandler)
+	http.HandleFunc("/repo/", web.RepoHandler)
 	http.HandleFunc("/search", web.SearchHandler)
 	http.HandleFunc("/api/search", web.SearchAPIHandler)
+	http.HandleFunc("/api/parse-report", web.ParseReportHandler)
+	http.HandleFunc("/api/status", web.StatusHandler)
+	http.HandleFunc("/api/changes", web.ChangesHandler)
+	http.HandleFunc("/api/compare", web.CompareAPIHandler)
+	http.HandleFunc("/api/v1/", web.APIv1Handler)
+	http.HandleFunc("/api/graphql", web.GraphQLHandler)
+	http.HandleFunc("/api/export/", web.ExportHandler)
+	http.HandleFunc("/healthz", web.HealthzHandler)
+	http.HandleFunc("/readyz", web.ReadyzHandler)
+	http.HandleFunc("/api/admin/refresh", web.AdminRefreshHandler)
+	http.HandleFunc("/api/webhook/github", web.GitHubWebhookHandler)
 	http.HandleFunc("/card/", web.CardHandler)
 
-	log.Println("Server starting on http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	srv := &http.Server{Addr: ":8080"}
+	go func() {
+		log.Println("Server starting on http://localhost:8080")
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // a second signal kills the process
+	log.Println("Shutting down: draining requests and stopping the scraper")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown: %v", err)
+	}
+	if err := runner.Stop(shutdownCtx); err != nil {
+		log.Printf("Scraper shutdown: %v", err)
+	}
+	log.Println("Server stopped")
 }
@@ -0,0 +1,132 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Completeness describes how much of the discovered release history is
+// cached. Aggregations such as the leaderboard, contributor history and
+// first-time flags are only exact once Complete reports true.
+type Completeness struct {
+	Total       int  // versions discovered
+	Cached      int  // versions parsed and held in the cache
+	Failed      int  // versions the last backfill could not fetch
+	Backfilling bool // a backfill is currently running
+}
+
+// Complete reports whether every discovered version is cached.
+func (c Completeness) Complete() bool {
+	return c.Total > 0 && c.Cached >= c.Total
+}
+
+// Percent is the cached share of discovered versions, 0-100.
+func (c Completeness) Percent() int {
+	if c.Total == 0 {
+		return 0
+	}
+	return c.Cached * 100 / c.Total
+}
+
+// backfillBatch is how many fetches a backfill makes between progress logs
+// and Index rebuilds.
+const backfillBatch = 10
+
+var (
+	backfillMu      sync.Mutex
+	backfillRunning bool
+	backfillFailed  int
+)
+
+// GetCompleteness returns the current data-completeness state.
+func GetCompleteness() Completeness {
+	versionsMu.RLock()
+	versions := availableVersions
+	versionsMu.RUnlock()
+
+	c := Completeness{Total: len(versions)}
+	mu.RLock()
+	for _, v := range versions {
+		if _, ok := cached[v]; ok {
+			c.Cached++
+		}
+	}
+	mu.RUnlock()
+
+	backfillMu.Lock()
+	c.Backfilling = backfillRunning
+	c.Failed = backfillFailed
+	backfillMu.Unlock()
+	return c
+}
+
+// Backfill fetches every discovered version that is not yet cached, at most
+// concurrency at a time, logging progress as it goes. Only one backfill runs
+// at once; a call made while another is in progress returns nil immediately.
+// Fetch failures are returned joined; cancelling ctx stops the backfill.
+func Backfill(ctx context.Context, concurrency int) error {
+	backfillMu.Lock()
+	if backfillRunning {
+		backfillMu.Unlock()
+		return nil
+	}
+	backfillRunning = true
+	backfillMu.Unlock()
+
+	var missing []Version
+	versionsMu.RLock()
+	versions := availableVersions
+	versionsMu.RUnlock()
+	mu.RLock()
+	for _, v := range versions {
+		if _, ok := cached[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	mu.RUnlock()
+
+	if len(missing) > 0 {
+		log.Printf("scraper: backfilling %d of %d versions", len(missing), len(versions))
+	}
+
+	// Rebuilding the Index per batch rather than per fetch fills the site in
+	// as the backfill goes without aggregating the cache hundreds of times.
+	var (
+		progressMu sync.Mutex
+		done       int
+		failed     int
+		pending    bool // releases cached since the last rebuild
+	)
+	err := fetchEach(ctx, missing, concurrency, func(ctx context.Context, version Version) error {
+		_, changed, err := fetchAndCache(ctx, version)
+
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		done++
+		pending = pending || changed
+		if err != nil {
+			failed++
+			log.Printf("scraper: backfill: %v", err)
+			recordFetchError(version, err)
+		}
+		if done%backfillBatch == 0 || done == len(missing) {
+			log.Printf("scraper: backfill progress %d/%d (%d failed)", done, len(missing), failed)
+			if pending {
+				rebuildIndex()
+				pending = false
+			}
+		}
+		return err
+	})
+	if pending {
+		// Cancelled before the last batch completed
+		rebuildIndex()
+	}
+
+	backfillMu.Lock()
+	backfillRunning = false
+	backfillFailed = failed
+	backfillMu.Unlock()
+	return err
+}
@@ -0,0 +1,41 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackfillRebuildsIndex(t *testing.T) {
+	dir := t.TempDir()
+	const n = 23 // five prefetched, then a full batch and a partial one
+	for minor := 1; minor <= n; minor++ {
+		md := fmt.Sprintf("## Thank you\n\n* [@u%d (U)](https://github.com/u%d): x [PR #%d](https://github.com/o/r/pull/%d)\n", minor, minor, minor, minor)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("v3_%d.md", minor)), []byte(md), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	previous := GetConfig()
+	cfg := DefaultConfig()
+	cfg.Dir = dir
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Configure(previous) })
+
+	if _, err := Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if err := Backfill(context.Background(), 4); err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	index := GetIndex()
+	for minor := 1; minor <= n; minor++ {
+		v := Version{Major: 3, Minor: minor}
+		if _, ok := index.Release(v); !ok {
+			t.Errorf("release %s not in the index", v)
+		}
+	}
+}
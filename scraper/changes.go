@@ -0,0 +1,189 @@
+package scraper
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Change is what one re-fetch changed in a release's pull request
+// contributors: people thanked late, PRs added or dropped in an edit of the
+// notes. Releases seen for the first time are reported with NewRelease set
+// and everything in them as added, but only when they are newer than every
+// cached release once the first refresh has completed; backfilling older
+// releases or loading them at startup is not a change.
+type Change struct {
+	Seq        uint64    `json:"seq"` // increases with every change, for polling the feed
+	Version    Version   `json:"version"`
+	At         time.Time `json:"at"`
+	NewRelease bool      `json:"new_release"`
+
+	ContributorsAdded   []string   `json:"contributors_added"` // GitHub logins
+	ContributorsRemoved []string   `json:"contributors_removed"`
+	PRsAdded            []PRChange `json:"prs_added"`
+	PRsRemoved          []PRChange `json:"prs_removed"`
+}
+
+// PRChange is a pull request added to or removed from a release.
+type PRChange struct {
+	GitHubUser string `json:"github_user"`
+	Repo       string `json:"repo"`
+	Number     string `json:"number"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+}
+
+// Empty reports whether c records no difference.
+func (c Change) Empty() bool {
+	return len(c.ContributorsAdded) == 0 && len(c.ContributorsRemoved) == 0 &&
+		len(c.PRsAdded) == 0 && len(c.PRsRemoved) == 0
+}
+
+// maxChanges is how many changes GetChanges remembers.
+const maxChanges = 500
+
+var (
+	changesMu   sync.Mutex
+	changeSeq   uint64
+	changeLog   []Change // oldest first, at most maxChanges
+	subscribers = make(map[chan Change]struct{})
+)
+
+// SubscribeChanges returns a channel that receives every Change from now
+// on, and a function that unsubscribes and closes the channel. The channel
+// holds up to buffer changes; a subscriber that falls further behind misses
+// changes rather than stalling the scraper, and can catch up from
+// GetChanges by Seq.
+func SubscribeChanges(buffer int) (<-chan Change, func()) {
+	ch := make(chan Change, buffer)
+	changesMu.Lock()
+	subscribers[ch] = struct{}{}
+	changesMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			changesMu.Lock()
+			delete(subscribers, ch)
+			changesMu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// GetChanges returns the remembered changes with a Seq above since, newest
+// first.
+func GetChanges(since uint64) []Change {
+	changesMu.Lock()
+	defer changesMu.Unlock()
+	var out []Change
+	for i := len(changeLog) - 1; i >= 0 && changeLog[i].Seq > since; i-- {
+		out = append(out, changeLog[i])
+	}
+	return out
+}
+
+// publishChange records c, unless it is empty, and sends it to subscribers.
+func publishChange(c Change) {
+	if c.Empty() {
+		return
+	}
+	changesMu.Lock()
+	changeSeq++
+	c.Seq = changeSeq
+	c.At = time.Now()
+	changeLog = append(changeLog, c)
+	if len(changeLog) > maxChanges {
+		changeLog = append([]Change(nil), changeLog[len(changeLog)-maxChanges:]...)
+	}
+	for ch := range subscribers {
+		select {
+		case ch <- c:
+		default:
+		}
+	}
+	changesMu.Unlock()
+
+	log.Printf("scraper: %s changed: +%d/-%d contributors, +%d/-%d PRs",
+		c.Version.ID(), len(c.ContributorsAdded), len(c.ContributorsRemoved), len(c.PRsAdded), len(c.PRsRemoved))
+}
+
+// diffReleases compares two parses of a release. Contributors are matched
+// by identity, so a renamed login is not a change, and PRs by URL.
+func diffReleases(old, cur Release) Change {
+	c := Change{Version: cur.Version}
+	oldUsers, curUsers := releaseUsers(old), releaseUsers(cur)
+	for key, login := range curUsers {
+		if _, ok := oldUsers[key]; !ok {
+			c.ContributorsAdded = append(c.ContributorsAdded, login)
+		}
+	}
+	for key, login := range oldUsers {
+		if _, ok := curUsers[key]; !ok {
+			c.ContributorsRemoved = append(c.ContributorsRemoved, login)
+		}
+	}
+	oldPRs, curPRs := releasePRs(old), releasePRs(cur)
+	for url, pr := range curPRs {
+		if _, ok := oldPRs[url]; !ok {
+			c.PRsAdded = append(c.PRsAdded, pr)
+		}
+	}
+	for url, pr := range oldPRs {
+		if _, ok := curPRs[url]; !ok {
+			c.PRsRemoved = append(c.PRsRemoved, pr)
+		}
+	}
+	sort.Strings(c.ContributorsAdded)
+	sort.Strings(c.ContributorsRemoved)
+	sortPRChanges(c.PRsAdded)
+	sortPRChanges(c.PRsRemoved)
+	return c
+}
+
+// releaseUsers maps the canonical identity of each PR contributor in r to
+// the login the notes use.
+func releaseUsers(r Release) map[string]string {
+	users := make(map[string]string, len(r.Contributors))
+	for _, c := range r.Contributors {
+		users[CanonicalUser(c.GitHubUser)] = c.GitHubUser
+	}
+	return users
+}
+
+func releasePRs(r Release) map[string]PRChange {
+	prs := make(map[string]PRChange)
+	for _, c := range r.Contributors {
+		for _, pr := range c.PRs {
+			prs[pr.URL] = PRChange{
+				GitHubUser: c.GitHubUser,
+				Repo:       pr.Repo,
+				Number:     pr.Number,
+				Title:      pr.Title,
+				URL:        pr.URL,
+			}
+		}
+	}
+	return prs
+}
+
+func sortPRChanges(prs []PRChange) {
+	sort.Slice(prs, func(i, j int) bool { return prs[i].URL < prs[j].URL })
+}
+
+// releaseChanged publishes the difference between the cached copy of a
+// release and its new parse; hadPrevious tells whether there was a cached
+// copy, and newest whether the release is newer than every cached one.
+func releaseChanged(previous Release, hadPrevious bool, cur Release, newest bool) {
+	if !hadPrevious {
+		if !newest || !IsReady() {
+			return
+		}
+		c := diffReleases(Release{}, cur)
+		c.NewRelease = true
+		publishChange(c)
+		return
+	}
+	publishChange(diffReleases(previous, cur))
+}
@@ -0,0 +1,61 @@
+package scraper
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffReleases(t *testing.T) {
+	pr := func(n int) PR {
+		return PR{Number: fmt.Sprint(n), Repo: "o/r", URL: fmt.Sprintf("https://github.com/o/r/pull/%d", n)}
+	}
+	old := Release{Version: Version{Major: 1, Minor: 2}, Contributors: []Contributor{
+		{GitHubUser: "alice", PRs: []PR{pr(1), pr(2)}},
+		{GitHubUser: "bob", PRs: []PR{pr(3)}},
+	}}
+	cur := Release{Version: Version{Major: 1, Minor: 2}, Contributors: []Contributor{
+		{GitHubUser: "Alice", PRs: []PR{pr(1)}},
+		{GitHubUser: "carol", PRs: []PR{pr(4)}},
+	}}
+
+	c := diffReleases(old, cur)
+	if fmt.Sprint(c.ContributorsAdded) != "[carol]" || fmt.Sprint(c.ContributorsRemoved) != "[bob]" {
+		t.Errorf("contributors added %v, removed %v", c.ContributorsAdded, c.ContributorsRemoved)
+	}
+	if len(c.PRsAdded) != 1 || c.PRsAdded[0].Number != "4" || c.PRsAdded[0].GitHubUser != "carol" {
+		t.Errorf("PRs added %+v", c.PRsAdded)
+	}
+	if len(c.PRsRemoved) != 2 || c.PRsRemoved[0].Number != "2" || c.PRsRemoved[1].Number != "3" {
+		t.Errorf("PRs removed %+v", c.PRsRemoved)
+	}
+	if !diffReleases(cur, cur).Empty() {
+		t.Error("diff of a release with itself is not empty")
+	}
+}
+
+func TestSubscribeChanges(t *testing.T) {
+	ch, unsubscribe := SubscribeChanges(1)
+	since := GetChanges(0)
+	var last uint64
+	if len(since) > 0 {
+		last = since[0].Seq
+	}
+
+	publishChange(Change{Version: Version{Major: 1, Minor: 3}}) // empty, dropped
+	publishChange(Change{Version: Version{Major: 1, Minor: 3}, ContributorsAdded: []string{"a"}})
+	publishChange(Change{Version: Version{Major: 1, Minor: 4}, ContributorsAdded: []string{"b"}}) // buffer full
+
+	got := <-ch
+	if got.Version != (Version{Major: 1, Minor: 3}) || got.Seq != last+1 {
+		t.Errorf("received %+v, want 1.3 with Seq %d", got, last+1)
+	}
+	if feed := GetChanges(last); len(feed) != 2 || feed[0].Version != (Version{Major: 1, Minor: 4}) {
+		t.Errorf("GetChanges(%d) = %+v, want 1.4 then 1.3", last, feed)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-ch; ok {
+		t.Error("channel still open after unsubscribe")
+	}
+}
@@ -0,0 +1,177 @@
+package scraper
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// growersLimit caps Comparison.TopGrowers.
+const growersLimit = 10
+
+// Comparison contrasts the contributors of two releases and those in
+// between, e.g. for a release retrospective. The baseline is the From
+// release; the range is every cached release after it up to and including
+// To. Only pull requests count; documentation, issue tracking and
+// localization are left out.
+type Comparison struct {
+	From     Version   `json:"from"`
+	To       Version   `json:"to"`
+	Releases []Version `json:"releases"` // cached releases from From to To, oldest first
+
+	// New contributors made their first contribution in the range;
+	// Returning ones contributed in the range and at or before From.
+	// DroppedOff contributed to From but to nothing in the range.
+	New        []ComparedContributor `json:"new"`
+	Returning  []ComparedContributor `json:"returning"`
+	DroppedOff []ComparedContributor `json:"dropped_off"`
+
+	// Repos compares the PRs per repository in From and To, biggest change
+	// first. TopGrowers are the contributors with the most more PRs in To
+	// than in From.
+	Repos      []RepoDelta           `json:"repos"`
+	TopGrowers []ComparedContributor `json:"top_growers"`
+}
+
+// ComparedContributor is one contributor's part in a Comparison.
+type ComparedContributor struct {
+	GitHubUser   string  `json:"github_user"`
+	Name         string  `json:"name"`
+	AvatarURL    string  `json:"avatar_url"`
+	FirstRelease Version `json:"first_release,omitzero"` // across all cached releases
+	FromPRs      int     `json:"from_prs"`               // PRs in the From release
+	ToPRs        int     `json:"to_prs"`                 // PRs in the To release
+	RangePRs     int     `json:"range_prs"`              // PRs after From up to and including To
+	Delta        int     `json:"delta"`                  // ToPRs - FromPRs
+}
+
+// RepoDelta is the change in one repository's PRs between two releases.
+type RepoDelta struct {
+	Repo    string `json:"repo"`
+	FromPRs int    `json:"from_prs"`
+	ToPRs   int    `json:"to_prs"`
+	Delta   int    `json:"delta"`
+}
+
+// Compare contrasts the cached releases from and to; from must be the older
+// one. Releases that are not cached count as having no contributors.
+func (ix *Index) Compare(from, to Version) Comparison {
+	from, to = from.Base(), to.Base()
+	c := Comparison{From: from, To: to, Releases: []Version{}}
+
+	people := make(map[string]*ComparedContributor)
+	contributor := func(login string) *ComparedContributor {
+		key := CanonicalUser(login)
+		if p, ok := people[key]; ok {
+			return p
+		}
+		p := &ComparedContributor{GitHubUser: login}
+		if h := ix.Contributor(login); h != nil {
+			p.GitHubUser, p.Name, p.AvatarURL, p.FirstRelease = h.GitHubUser, h.Name, h.AvatarURL, h.FirstRelease
+		}
+		people[key] = p
+		return p
+	}
+	repos := make(map[string]*RepoDelta)
+	repo := func(name string) *RepoDelta {
+		key := strings.ToLower(name)
+		if r, ok := repos[key]; ok {
+			return r
+		}
+		r := &RepoDelta{Repo: name}
+		repos[key] = r
+		return r
+	}
+
+	for _, rel := range ix.releases {
+		if rel.Version.Less(from) || to.Less(rel.Version) {
+			continue
+		}
+		c.Releases = append(c.Releases, rel.Version)
+		for _, con := range rel.Contributors {
+			if len(con.PRs) == 0 {
+				continue
+			}
+			p := contributor(con.GitHubUser)
+			if p.Name == "" {
+				p.Name = con.Name
+			}
+			switch rel.Version {
+			case from:
+				p.FromPRs += len(con.PRs)
+			case to:
+				p.ToPRs += len(con.PRs)
+			}
+			if rel.Version != from {
+				p.RangePRs += len(con.PRs)
+			}
+			for _, pr := range con.PRs {
+				switch rel.Version {
+				case from:
+					repo(pr.Repo).FromPRs++
+				case to:
+					repo(pr.Repo).ToPRs++
+				}
+			}
+		}
+	}
+	slices.SortFunc(c.Releases, Version.Compare)
+
+	for _, p := range people {
+		p.Delta = p.ToPRs - p.FromPRs
+		switch {
+		case p.RangePRs == 0:
+			c.DroppedOff = append(c.DroppedOff, *p)
+		case p.FirstRelease.IsZero() || from.Less(p.FirstRelease):
+			c.New = append(c.New, *p)
+		default:
+			c.Returning = append(c.Returning, *p)
+		}
+		if p.Delta > 0 {
+			c.TopGrowers = append(c.TopGrowers, *p)
+		}
+	}
+	byLogin := func(a, b ComparedContributor) int {
+		return strings.Compare(strings.ToLower(a.GitHubUser), strings.ToLower(b.GitHubUser))
+	}
+	byRangePRs := func(a, b ComparedContributor) int {
+		return cmp.Or(b.RangePRs-a.RangePRs, byLogin(a, b))
+	}
+	slices.SortFunc(c.New, byRangePRs)
+	slices.SortFunc(c.Returning, byRangePRs)
+	slices.SortFunc(c.DroppedOff, func(a, b ComparedContributor) int {
+		return cmp.Or(b.FromPRs-a.FromPRs, byLogin(a, b))
+	})
+	slices.SortFunc(c.TopGrowers, func(a, b ComparedContributor) int {
+		return cmp.Or(b.Delta-a.Delta, b.ToPRs-a.ToPRs, byLogin(a, b))
+	})
+	if len(c.TopGrowers) > growersLimit {
+		c.TopGrowers = c.TopGrowers[:growersLimit]
+	}
+
+	for _, r := range repos {
+		r.Delta = r.ToPRs - r.FromPRs
+		c.Repos = append(c.Repos, *r)
+	}
+	slices.SortFunc(c.Repos, func(a, b RepoDelta) int {
+		return cmp.Or(abs(b.Delta)-abs(a.Delta), b.ToPRs-a.ToPRs, strings.Compare(a.Repo, b.Repo))
+	})
+
+	// Empty lists encode as [], not null
+	for _, list := range []*[]ComparedContributor{&c.New, &c.Returning, &c.DroppedOff, &c.TopGrowers} {
+		if *list == nil {
+			*list = []ComparedContributor{}
+		}
+	}
+	if c.Repos == nil {
+		c.Repos = []RepoDelta{}
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,54 @@
+package scraper
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	prs := func(repo string, n int) []PR {
+		var out []PR
+		for i := 0; i < n; i++ {
+			out = append(out, PR{Repo: repo, Number: fmt.Sprint(i)})
+		}
+		return out
+	}
+	v := func(minor int) Version { return Version{Major: 1, Minor: minor} }
+	ix := NewIndex([]Release{
+		{Version: v(1), Contributors: []Contributor{{GitHubUser: "old", PRs: prs("o/a", 1)}}},
+		{Version: v(2), Contributors: []Contributor{
+			{GitHubUser: "old", PRs: prs("o/a", 1)},
+			{GitHubUser: "gone", PRs: prs("o/b", 2)},
+		}},
+		{Version: v(3), Contributors: []Contributor{{GitHubUser: "mid", PRs: prs("o/a", 1)}}},
+		{Version: v(4), Contributors: []Contributor{
+			{GitHubUser: "Old", PRs: prs("o/a", 3)},
+			{GitHubUser: "fresh", PRs: prs("o/c", 1)},
+		}},
+		{Version: v(5), Contributors: []Contributor{{GitHubUser: "later", PRs: prs("o/a", 1)}}},
+	})
+
+	c := ix.Compare(v(2), v(4))
+	logins := func(list []ComparedContributor) string {
+		var s []string
+		for _, p := range list {
+			s = append(s, p.GitHubUser)
+		}
+		return fmt.Sprint(s)
+	}
+	if fmt.Sprint(c.Releases) != "[1.2 1.3 1.4]" {
+		t.Errorf("releases %v", c.Releases)
+	}
+	if logins(c.New) != "[fresh mid]" || logins(c.Returning) != "[Old]" || logins(c.DroppedOff) != "[gone]" {
+		t.Errorf("new %s, returning %s, dropped off %s", logins(c.New), logins(c.Returning), logins(c.DroppedOff))
+	}
+	if old := c.Returning[0]; old.FromPRs != 1 || old.ToPRs != 3 || old.RangePRs != 3 || old.Delta != 2 {
+		t.Errorf("old = %+v", old)
+	}
+	if logins(c.TopGrowers) != "[Old fresh]" {
+		t.Errorf("top growers %s", logins(c.TopGrowers))
+	}
+	if fmt.Sprint(c.Repos) != "[{o/a 1 3 2} {o/b 2 0 -2} {o/c 0 1 1}]" {
+		t.Errorf("repos %v", c.Repos)
+	}
+}
@@ -0,0 +1,262 @@
+package scraper
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config describes where release notes are read from and how eagerly they
+// are fetched. The defaults point at microsoft/vscode-docs on GitHub; any
+// repository using the same "Thank you" release-notes format can be
+// substituted.
+type Config struct {
+	Owner      string // repository owner, e.g. "microsoft"
+	Repo       string // repository name, e.g. "vscode-docs"
+	Ref        string // branch, tag or commit SHA
+	Path       string // directory holding the vX_YY.md files
+	APIBaseURL string // GitHub REST API root, used for discovery
+	RawBaseURL string // raw file host, used for fetching notes
+	Token      string // optional GitHub token for API requests
+
+	// Dir, when set, is a local directory of vX_YY.md files (e.g. the
+	// release-notes folder of a vscode-docs clone) that is read instead of
+	// the remote repository; the fields above are then ignored.
+	Dir string
+
+	// CacheDir, when set, is a directory where parsed releases and the
+	// version list are persisted, and loaded from on startup.
+	CacheDir string
+
+	// Backfill fetches every discovered release in the background after
+	// each refresh, not just the newest few.
+	Backfill bool
+	// Concurrency bounds how many release notes are fetched in parallel.
+	Concurrency int
+	// FetchTimeout is the deadline for fetching a single release.
+	FetchTimeout time.Duration
+	// RefreshInterval is the delay between background refreshes, and
+	// RefreshJitter the most random delay added to it.
+	RefreshInterval time.Duration
+	RefreshJitter   time.Duration
+	// Enrich fetches merge date, labels, diff size and linked issues for
+	// every PR from the GitHub API. Needs a token for any real volume.
+	Enrich bool
+
+	// AliasesFile is an optional JSON file mapping former GitHub logins to
+	// current ones, so renamed accounts aggregate as one contributor.
+	AliasesFile string
+	// ResolveUserIDs additionally merges logins credited for PRs of the
+	// same GitHub user ID, as recorded by Enrich.
+	ResolveUserIDs bool
+
+	// AdminToken is the bearer token the admin refresh endpoint requires;
+	// the endpoint is disabled when it is empty.
+	AdminToken string
+	// WebhookSecret verifies the signatures of GitHub push webhooks; the
+	// webhook receiver is disabled when it is empty.
+	WebhookSecret string
+}
+
+// DefaultConfig returns the configuration for microsoft/vscode-docs.
+func DefaultConfig() Config {
+	return Config{
+		Owner:        "microsoft",
+		Repo:         "vscode-docs",
+		Ref:          "main",
+		Path:         "release-notes",
+		APIBaseURL:   "https://api.github.com",
+		RawBaseURL:   "https://raw.githubusercontent.com",
+		Backfill:     true,
+		Concurrency:  4,
+		FetchTimeout: 30 * time.Second,
+
+		RefreshInterval: time.Hour,
+		RefreshJitter:   time.Minute,
+	}
+}
+
+// ConfigFromEnv returns DefaultConfig overridden by any of the
+// RELEASE_NOTES_OWNER, RELEASE_NOTES_REPO, RELEASE_NOTES_REF,
+// RELEASE_NOTES_PATH, RELEASE_NOTES_DIR, GITHUB_API_URL, GITHUB_RAW_URL,
+// GITHUB_TOKEN, SCRAPER_CACHE_DIR, SCRAPER_BACKFILL, SCRAPER_CONCURRENCY, SCRAPER_FETCH_TIMEOUT,
+// SCRAPER_REFRESH_INTERVAL, SCRAPER_REFRESH_JITTER, SCRAPER_ENRICH, SCRAPER_ALIASES, SCRAPER_RESOLVE_IDS, ADMIN_TOKEN and
+// GITHUB_WEBHOOK_SECRET environment variables.
+func ConfigFromEnv() Config {
+	c := DefaultConfig()
+	setFromEnv(&c.Owner, "RELEASE_NOTES_OWNER")
+	setFromEnv(&c.Repo, "RELEASE_NOTES_REPO")
+	setFromEnv(&c.Ref, "RELEASE_NOTES_REF")
+	setFromEnv(&c.Path, "RELEASE_NOTES_PATH")
+	setFromEnv(&c.Dir, "RELEASE_NOTES_DIR")
+	setFromEnv(&c.APIBaseURL, "GITHUB_API_URL")
+	setFromEnv(&c.RawBaseURL, "GITHUB_RAW_URL")
+	setFromEnv(&c.Token, "GITHUB_TOKEN")
+	setFromEnv(&c.CacheDir, "SCRAPER_CACHE_DIR")
+	if v, err := strconv.ParseBool(os.Getenv("SCRAPER_BACKFILL")); err == nil {
+		c.Backfill = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("SCRAPER_CONCURRENCY")); err == nil && v > 0 {
+		c.Concurrency = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("SCRAPER_FETCH_TIMEOUT")); err == nil && v > 0 {
+		c.FetchTimeout = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("SCRAPER_REFRESH_INTERVAL")); err == nil && v > 0 {
+		c.RefreshInterval = v
+	}
+	if v, err := time.ParseDuration(os.Getenv("SCRAPER_REFRESH_JITTER")); err == nil && v >= 0 {
+		c.RefreshJitter = v
+	}
+	if v, err := strconv.ParseBool(os.Getenv("SCRAPER_ENRICH")); err == nil {
+		c.Enrich = v
+	}
+	setFromEnv(&c.AliasesFile, "SCRAPER_ALIASES")
+	if v, err := strconv.ParseBool(os.Getenv("SCRAPER_RESOLVE_IDS")); err == nil {
+		c.ResolveUserIDs = v
+	}
+	setFromEnv(&c.AdminToken, "ADMIN_TOKEN")
+	setFromEnv(&c.WebhookSecret, "GITHUB_WEBHOOK_SECRET")
+	return c
+}
+
+func setFromEnv(dst *string, key string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+// RegisterFlags binds command-line flags to c's fields, using the current
+// values as defaults. The token and other secrets are deliberately env-only.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Owner, "notes-owner", c.Owner, "owner of the release-notes repository")
+	fs.StringVar(&c.Repo, "notes-repo", c.Repo, "name of the release-notes repository")
+	fs.StringVar(&c.Ref, "notes-ref", c.Ref, "branch, tag or commit to read release notes from")
+	fs.StringVar(&c.Path, "notes-path", c.Path, "directory of release-notes files within the repository")
+	fs.StringVar(&c.APIBaseURL, "github-api-url", c.APIBaseURL, "base URL of the GitHub REST API")
+	fs.StringVar(&c.RawBaseURL, "github-raw-url", c.RawBaseURL, "base URL for raw file downloads")
+	fs.StringVar(&c.Dir, "notes-dir", c.Dir, "local directory of release-notes files; overrides the remote repository")
+	fs.StringVar(&c.CacheDir, "cache-dir", c.CacheDir, "directory where parsed releases are persisted and loaded from on startup")
+	fs.BoolVar(&c.Backfill, "backfill", c.Backfill, "fetch every discovered release in the background, not just the newest")
+	fs.IntVar(&c.Concurrency, "concurrency", c.Concurrency, "maximum number of release notes fetched in parallel")
+	fs.DurationVar(&c.FetchTimeout, "fetch-timeout", c.FetchTimeout, "deadline for fetching a single release")
+	fs.DurationVar(&c.RefreshInterval, "refresh-interval", c.RefreshInterval, "delay between background refreshes")
+	fs.DurationVar(&c.RefreshJitter, "refresh-jitter", c.RefreshJitter, "maximum random delay added to each refresh interval")
+	fs.BoolVar(&c.Enrich, "enrich", c.Enrich, "fetch PR metadata (merge date, labels, diff size) from the GitHub API")
+	fs.StringVar(&c.AliasesFile, "aliases", c.AliasesFile, "JSON file mapping former GitHub logins to current ones")
+	fs.BoolVar(&c.ResolveUserIDs, "resolve-ids", c.ResolveUserIDs, "merge contributors whose enriched PRs have the same author user ID (needs -enrich)")
+}
+
+// Validate reports whether c has every field needed to fetch release notes.
+func (c Config) Validate() error {
+	if c.Concurrency < 1 {
+		return fmt.Errorf("scraper config: concurrency must be at least 1")
+	}
+	if c.FetchTimeout <= 0 {
+		return fmt.Errorf("scraper config: fetch timeout must be positive")
+	}
+	if c.RefreshInterval <= 0 {
+		return fmt.Errorf("scraper config: refresh interval must be positive")
+	}
+	if c.RefreshJitter < 0 {
+		return fmt.Errorf("scraper config: refresh jitter must not be negative")
+	}
+	if c.Dir != "" {
+		info, err := os.Stat(c.Dir)
+		if err != nil {
+			return fmt.Errorf("scraper config: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("scraper config: %s is not a directory", c.Dir)
+		}
+		return nil
+	}
+	for _, f := range []struct{ name, value string }{
+		{"owner", c.Owner},
+		{"repo", c.Repo},
+		{"ref", c.Ref},
+		{"API base URL", c.APIBaseURL},
+		{"raw base URL", c.RawBaseURL},
+	} {
+		if f.value == "" {
+			return fmt.Errorf("scraper config: %s is required", f.name)
+		}
+	}
+	return nil
+}
+
+// contentsURL is the GitHub contents API URL listing the release-notes directory.
+func (c Config) contentsURL() string {
+	return fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s",
+		strings.TrimRight(c.APIBaseURL, "/"), c.Owner, c.Repo, c.trimmedPath(), url.QueryEscape(c.Ref))
+}
+
+// rawURL is the download URL of the release notes for version at ref, a
+// branch, tag or commit SHA; an empty ref means Config.Ref.
+func (c Config) rawURL(version Version, ref string) string {
+	if ref == "" {
+		ref = c.Ref
+	}
+	path := version.ID() + ".md"
+	if p := c.trimmedPath(); p != "" {
+		path = p + "/" + path
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/%s",
+		strings.TrimRight(c.RawBaseURL, "/"), c.Owner, c.Repo, ref, path)
+}
+
+func (c Config) trimmedPath() string {
+	return strings.Trim(c.Path, "/")
+}
+
+var (
+	configMu sync.RWMutex
+	config   = ConfigFromEnv()
+)
+
+// Configure replaces the release-notes source, loads the alias file and
+// opens the cache directory, warming the cache from it. It is meant to be
+// called once at startup, before StartBackground; remembered HTTP
+// validators are discarded since they belong to the old URLs.
+func Configure(c Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	if c.AliasesFile != "" {
+		if err := LoadAliases(c.AliasesFile); err != nil {
+			return fmt.Errorf("scraper config: aliases: %w", err)
+		}
+	} else {
+		SetAliases(nil)
+	}
+	configMu.Lock()
+	config = c
+	configMu.Unlock()
+	openCacheDir(c.CacheDir)
+
+	validatorsMu.Lock()
+	validators = make(map[string]validator)
+	lastListing = nil
+	validatorsMu.Unlock()
+
+	localModTimesMu.Lock()
+	localModTimes = make(map[string]time.Time)
+	localModTimesMu.Unlock()
+
+	rateMu.Lock()
+	rateLimit.Authenticated = c.Token != ""
+	rateMu.Unlock()
+	return nil
+}
+
+// GetConfig returns the active release-notes source configuration.
+func GetConfig() Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return config
+}
@@ -0,0 +1,332 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// prDetails is the subset of a GitHub pull request that enrichment stores on PR.
+type prDetails struct {
+	// NotFound is set when the API has no such pull request, e.g. for an
+	// issue link in the notes; the PR is then left unenriched for good.
+	NotFound bool
+
+	MergedAt     time.Time
+	Labels       []string
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+	LinkedIssues []string
+	AuthorID     int64
+	AuthorLogin  string
+}
+
+var (
+	prDetailsMu sync.RWMutex
+	// prDetailsCache maps "owner/repo#number" to details already fetched,
+	// so re-parsed releases can be re-enriched without API calls.
+	prDetailsCache = make(map[string]prDetails)
+)
+
+// errPRNotFound is returned by fetchPRDetails for a 404.
+var errPRNotFound = errors.New("no such pull request")
+
+func prKey(repo, number string) string {
+	return repo + "#" + number
+}
+
+// enrichable returns the contributor lists whose PRs are enriched:
+// contributors' and documentation PRs.
+func enrichable(r *Release) []*[]Contributor {
+	return []*[]Contributor{&r.Contributors, &r.Documentation}
+}
+
+// rememberEnriched seeds prDetailsCache from already-enriched PRs in r,
+// e.g. releases loaded from the Store.
+func rememberEnriched(r Release) {
+	prDetailsMu.Lock()
+	defer prDetailsMu.Unlock()
+	for _, list := range enrichable(&r) {
+		for _, c := range *list {
+			for _, pr := range c.PRs {
+				if pr.Enriched {
+					prDetailsCache[prKey(pr.Repo, pr.Number)] = prDetails{
+						MergedAt:     pr.MergedAt,
+						Labels:       pr.Labels,
+						Additions:    pr.Additions,
+						Deletions:    pr.Deletions,
+						ChangedFiles: pr.ChangedFiles,
+						LinkedIssues: pr.LinkedIssues,
+						AuthorID:     pr.AuthorID,
+						AuthorLogin:  pr.AuthorLogin,
+					}
+				}
+			}
+		}
+	}
+}
+
+// EnrichReleases adds GitHub API metadata (merge date, labels, diff size,
+// linked issues, author) to the PRs of every cached release, newest first. Details
+// are cached, so each PR is requested at most once; PRs the API has no
+// record of are remembered as not found. Other failures are logged and the
+// PR is retried on the next run. Enrichment stops early only when ctx is
+// done or the API rate limit is exhausted.
+func EnrichReleases(ctx context.Context, concurrency int) error {
+	for _, v := range GetAvailableVersions() {
+		mu.RLock()
+		r, ok := cached[v]
+		mu.RUnlock()
+		if !ok || fullyEnriched(r) {
+			continue
+		}
+
+		enriched, err := enrichRelease(ctx, r, concurrency)
+
+		mu.Lock()
+		// Only swap in if a refresh didn't replace the release meanwhile.
+		swapped := false
+		if cur, ok := cached[v]; ok && sameRelease(cur, r) {
+			cached[v] = enriched
+			swapped = true
+		}
+		mu.Unlock()
+		if swapped {
+			persistRelease(enriched)
+			rebuildIndex()
+		}
+
+		if err != nil {
+			var rle *RateLimitError
+			if errors.As(err, &rle) {
+				log.Printf("scraper: enrichment paused: %v", rle)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// fullyEnriched reports whether every PR in r is enriched or known not to
+// exist.
+func fullyEnriched(r Release) bool {
+	_, todo := withCachedDetails(r)
+	return len(todo) == 0
+}
+
+// sameRelease reports whether a and b are the same parse, by comparing
+// their PR sets; used to avoid overwriting a newer refresh.
+func sameRelease(a, b Release) bool {
+	as, bs := enrichable(&a), enrichable(&b)
+	for k := range as {
+		ac, bc := *as[k], *bs[k]
+		if len(ac) != len(bc) {
+			return false
+		}
+		for i := range ac {
+			if len(ac[i].PRs) != len(bc[i].PRs) {
+				return false
+			}
+			for j := range ac[i].PRs {
+				if ac[i].PRs[j].URL != bc[i].PRs[j].URL {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// withCachedDetails returns a copy of r whose PRs carry any details already
+// in prDetailsCache, plus pointers to the PRs that still need fetching.
+func withCachedDetails(r Release) (Release, []*PR) {
+	out := r
+	var todo []*PR
+	for _, list := range enrichable(&out) {
+		contributors := make([]Contributor, len(*list))
+		for i, c := range *list {
+			c.PRs = append([]PR(nil), c.PRs...)
+			contributors[i] = c
+			for j := range c.PRs {
+				pr := &contributors[i].PRs[j]
+				if pr.Enriched {
+					continue
+				}
+				prDetailsMu.RLock()
+				d, ok := prDetailsCache[prKey(pr.Repo, pr.Number)]
+				prDetailsMu.RUnlock()
+				switch {
+				case ok && d.NotFound:
+				case ok:
+					applyDetails(pr, d)
+				default:
+					todo = append(todo, pr)
+				}
+			}
+		}
+		*list = contributors
+	}
+	return out, todo
+}
+
+// enrichRelease returns a copy of r with as many PRs enriched as possible.
+// The returned release is valid even when err is non-nil.
+func enrichRelease(ctx context.Context, r Release, concurrency int) (Release, error) {
+	out, todo := withCachedDetails(r)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		rateLimitedMu sync.Mutex
+		rateLimited   error
+	)
+	err := fetchEach(ctx, todo, concurrency, func(ctx context.Context, pr *PR) error {
+		d, err := fetchPRDetails(ctx, pr.Repo, pr.Number)
+		var rle *RateLimitError
+		switch {
+		case errors.Is(err, errPRNotFound):
+			d = prDetails{NotFound: true}
+		case errors.As(err, &rle):
+			// Every further request would be refused too.
+			rateLimitedMu.Lock()
+			rateLimited = err
+			rateLimitedMu.Unlock()
+			cancel()
+			return err
+		case err != nil && ctx.Err() != nil:
+			return err
+		case err != nil:
+			// One bad PR must not hold up the rest; it is retried next run
+			log.Printf("scraper: enrich %s: %v", prKey(pr.Repo, pr.Number), err)
+			return nil
+		}
+		prDetailsMu.Lock()
+		prDetailsCache[prKey(pr.Repo, pr.Number)] = d
+		prDetailsMu.Unlock()
+		if !d.NotFound {
+			applyDetails(pr, d)
+		}
+		return nil
+	})
+	if rateLimited != nil {
+		return out, rateLimited
+	}
+	return out, err
+}
+
+func applyDetails(pr *PR, d prDetails) {
+	pr.MergedAt = d.MergedAt
+	pr.Labels = d.Labels
+	pr.Additions = d.Additions
+	pr.Deletions = d.Deletions
+	pr.ChangedFiles = d.ChangedFiles
+	pr.LinkedIssues = d.LinkedIssues
+	pr.AuthorID = d.AuthorID
+	pr.AuthorLogin = d.AuthorLogin
+	pr.Enriched = true
+}
+
+// closingIssueRe matches GitHub's closing keywords followed by an issue
+// reference: "Fixes #123", "closes microsoft/vscode#456", or a full issue URL.
+var closingIssueRe = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+(?:https://github\.com/([\w.-]+/[\w.-]+)/issues/(\d+)|([\w.-]+/[\w.-]+)?#(\d+))`)
+
+// linkedIssues extracts issue references closed by a PR body, qualified
+// with repo ("owner/repo#123") when the reference is repo-relative.
+func linkedIssues(repo, body string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, m := range closingIssueRe.FindAllStringSubmatch(body, -1) {
+		var ref string
+		switch {
+		case m[2] != "":
+			ref = prKey(m[1], m[2])
+		case m[3] != "":
+			ref = prKey(m[3], m[4])
+		default:
+			ref = prKey(repo, m[4])
+		}
+		if !seen[ref] {
+			seen[ref] = true
+			out = append(out, ref)
+		}
+	}
+	return out
+}
+
+// fetchPRDetails loads one pull request from the GitHub API.
+func fetchPRDetails(ctx context.Context, repo, number string) (prDetails, error) {
+	if err := checkRateLimit(); err != nil {
+		return prDetails{}, err
+	}
+	cfg := GetConfig()
+	url := fmt.Sprintf("%s/repos/%s/pulls/%s", strings.TrimRight(cfg.APIBaseURL, "/"), repo, number)
+	ctx, cancel := context.WithTimeout(ctx, cfg.FetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return prDetails{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	authorize(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return prDetails{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := recordRateLimit(resp); err != nil {
+		return prDetails{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return prDetails{}, errPRNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return prDetails{}, fmt.Errorf("GitHub API HTTP %d", resp.StatusCode)
+	}
+
+	var pr struct {
+		MergedAt *time.Time `json:"merged_at"`
+		Labels   []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		Additions    int    `json:"additions"`
+		Deletions    int    `json:"deletions"`
+		ChangedFiles int    `json:"changed_files"`
+		Body         string `json:"body"`
+		User         *struct {
+			ID    int64  `json:"id"`
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return prDetails{}, err
+	}
+
+	d := prDetails{
+		Additions:    pr.Additions,
+		Deletions:    pr.Deletions,
+		ChangedFiles: pr.ChangedFiles,
+		LinkedIssues: linkedIssues(repo, pr.Body),
+	}
+	if pr.MergedAt != nil {
+		d.MergedAt = *pr.MergedAt
+	}
+	for _, l := range pr.Labels {
+		d.Labels = append(d.Labels, l.Name)
+	}
+	// GitHub credits PRs of deleted accounts to its shared "ghost" user,
+	// which must not merge them into one contributor.
+	if pr.User != nil && !strings.EqualFold(pr.User.Login, "ghost") {
+		d.AuthorID, d.AuthorLogin = pr.User.ID, pr.User.Login
+	}
+	return d, nil
+}
@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEnrichRelease(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch r.URL.Path {
+		case "/repos/enrich-test/r/pulls/1", "/repos/enrich-test/docs/pulls/3":
+			w.Write([]byte(`{"additions": 5, "deletions": 2, "labels": [{"name": "bug"}], "user": {"id": 7, "login": "A"}}`))
+		case "/repos/enrich-test/r/pulls/2":
+			http.NotFound(w, r) // an issue linked as a PR
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	previous := GetConfig()
+	cfg := DefaultConfig()
+	cfg.APIBaseURL = srv.URL
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Configure(previous) })
+
+	pr := func(repo, n string) PR {
+		return PR{Repo: repo, Number: n, URL: "https://github.com/" + repo + "/pull/" + n}
+	}
+	rel := Release{
+		Version: Version{Major: 1, Minor: 2},
+		Contributors: []Contributor{
+			{GitHubUser: "a", PRs: []PR{pr("enrich-test/r", "2"), pr("enrich-test/r", "1")}},
+			{GitHubUser: "b", PRs: []PR{pr("enrich-test/r", "4")}},
+		},
+		Documentation: []Contributor{{GitHubUser: "c", PRs: []PR{pr("enrich-test/docs", "3")}}},
+	}
+
+	out, err := enrichRelease(context.Background(), rel, 2)
+	if err != nil {
+		t.Fatalf("enrichRelease: %v", err)
+	}
+	if a := out.Contributors[0].PRs; a[0].Enriched || !a[1].Enriched || a[1].Additions != 5 || a[1].AuthorID != 7 || a[1].AuthorLogin != "A" {
+		t.Errorf("contributor PRs %+v", a)
+	}
+	if out.Contributors[1].PRs[0].Enriched {
+		t.Error("PR whose fetch failed is enriched")
+	}
+	if d := out.Documentation[0].PRs[0]; !d.Enriched || d.Labels[0] != "bug" {
+		t.Errorf("documentation PR %+v", d)
+	}
+	if rel.Documentation[0].PRs[0].Enriched {
+		t.Error("enrichRelease modified its argument")
+	}
+
+	// The 404 is remembered; only the failed PR is asked for again
+	requests.Store(0)
+	out, _ = enrichRelease(context.Background(), out, 2)
+	if n := requests.Load(); n != 1 {
+		t.Errorf("second run made %d requests, want 1", n)
+	}
+	out.Contributors[1].PRs = nil
+	if !fullyEnriched(out) {
+		t.Error("release with only a not-found PR left is not fully enriched")
+	}
+}
@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// FetchError records a failure to fetch or parse one release.
+type FetchError struct {
+	Version Version
+	Err     error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("fetch %s: %v", e.Version.ID(), e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// fetchAndCache fetches version under the configured per-fetch deadline and,
+// if its content changed, stores it in the cache and the Store and
+// publishes the Change. The Index is left to the caller to rebuild, once
+// for a whole batch of fetches, if any reports changed.
+func fetchAndCache(ctx context.Context, version Version) (r Release, changed bool, err error) {
+	return fetchAndCacheAt(ctx, version, "")
+}
+
+// fetchAndCacheAt is fetchAndCache reading the notes at ref, as
+// fetchRelease does.
+func fetchAndCacheAt(ctx context.Context, version Version, ref string) (r Release, changed bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, GetConfig().FetchTimeout)
+	defer cancel()
+
+	r, changed, err = fetchRelease(ctx, version, ref)
+	if err != nil {
+		return Release{}, false, &FetchError{Version: version, Err: err}
+	}
+	if changed {
+		// Carry over PR details enriched from an earlier parse.
+		r, _ = withCachedDetails(r)
+		mu.Lock()
+		previous, hadPrevious := cached[version]
+		newest := true
+		for v := range cached {
+			if !v.Less(version) {
+				newest = false
+				break
+			}
+		}
+		cached[version] = r
+		mu.Unlock()
+		persistRelease(r)
+		releaseChanged(previous, hadPrevious, r, newest)
+	}
+	forgetFailure(version)
+	clearFetchError(version)
+	return r, changed, nil
+}
+
+// fetchEach calls fn for every item using at most concurrency goroutines.
+// Once ctx is done no further items are started. The errors returned by fn,
+// plus ctx's error if it ended the run early, are joined.
+func fetchEach[T any](ctx context.Context, items []T, concurrency int, fn func(ctx context.Context, item T) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		errsMu sync.Mutex
+		errs   []error
+	)
+	jobs := make(chan T)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(items); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				if err := fn(ctx, item); err != nil {
+					errsMu.Lock()
+					errs = append(errs, err)
+					errsMu.Unlock()
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, item := range items {
+		select {
+		case jobs <- item:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,102 @@
+//go:build ignore
+
+// Gengolden downloads the release notes snapshotted in testdata/golden from
+// microsoft/vscode-docs and trims them to the parts the parser reads. It is
+// run by "go generate ./scraper", which then rewrites the golden JSON; review
+// the diff of both before committing.
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// goldenVersions are the releases snapshotted in testdata/golden, one per
+// layout the notes have used: a flat list under "Thank You" (1.17), plain
+// "Contributions to" paragraphs (1.35), subsections per kind (1.60) and
+// the current notes (1.109).
+var goldenVersions = []string{"v1_17", "v1_35", "v1_60", "v1_109"}
+
+const notesURL = "https://raw.githubusercontent.com/microsoft/vscode-docs/main/release-notes/"
+
+func main() {
+	dir := filepath.Join("testdata", "golden")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		fail(err)
+	}
+	for _, id := range goldenVersions {
+		md, err := download(notesURL + id + ".md")
+		if err != nil {
+			fail(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, id+".md"), []byte(trimNotes(md)), 0o644); err != nil {
+			fail(err)
+		}
+	}
+}
+
+func download(url string) (string, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d for %s", resp.StatusCode, url)
+	}
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "gengolden:", err)
+	os.Exit(1)
+}
+
+var (
+	// headingRe matches a Markdown heading, as in parse.go.
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)[\s#]*$`)
+	// thankYouRe matches the heading of the acknowledgements.
+	thankYouRe = regexp.MustCompile(`(?i)^(#{1,6})\s+Thank you`)
+)
+
+// trimNotes keeps the parts of release notes the parser reads: the front
+// matter, the title and the "Thank you" section, up to the next heading of
+// its level or above.
+func trimNotes(md string) string {
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	var out []string
+	i := 0
+	if len(lines) > 0 && lines[0] == "---" {
+		for i = 1; i < len(lines) && lines[i] != "---"; i++ {
+		}
+		i++
+		out = append(out, lines[:min(i, len(lines))]...)
+	}
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "# ") {
+			out = append(out, lines[i], "")
+			break
+		}
+	}
+	for ; i < len(lines); i++ {
+		m := thankYouRe.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		end := i + 1
+		for ; end < len(lines); end++ {
+			if h := headingRe.FindStringSubmatch(strings.TrimSpace(lines[end])); h != nil && len(h[1]) <= len(m[1]) {
+				break
+			}
+		}
+		out = append(out, lines[i:end]...)
+		break
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n") + "\n"
+}
@@ -0,0 +1,81 @@
+package scraper
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// validator holds the cache validators a server returned for a URL.
+type validator struct {
+	etag         string
+	lastModified string
+}
+
+var (
+	validatorsMu sync.Mutex
+	validators   = make(map[string]validator)
+
+	// lastListing is the version list from the most recent successful
+	// discovery, reused when the contents API answers 304.
+	lastListing []Version
+
+	fetchHits      atomic.Int64
+	fetchDownloads atomic.Int64
+)
+
+// FetchStats counts how often conditional requests were answered from
+// cache (304 Not Modified) versus downloaded in full.
+type FetchStats struct {
+	Hits      int64
+	Downloads int64
+}
+
+// GetFetchStats returns the conditional fetch counters since startup.
+func GetFetchStats() FetchStats {
+	return FetchStats{
+		Hits:      fetchHits.Load(),
+		Downloads: fetchDownloads.Load(),
+	}
+}
+
+// addValidators sets If-None-Match / If-Modified-Since on req from the
+// validators remembered for its URL. Callers only do this when they still
+// hold the previously downloaded content.
+func addValidators(req *http.Request) {
+	validatorsMu.Lock()
+	v, ok := validators[req.URL.String()]
+	validatorsMu.Unlock()
+	if !ok {
+		return
+	}
+	if v.etag != "" {
+		req.Header.Set("If-None-Match", v.etag)
+	}
+	if v.lastModified != "" {
+		req.Header.Set("If-Modified-Since", v.lastModified)
+	}
+}
+
+// rememberValidators records the ETag / Last-Modified of a 200 response.
+func rememberValidators(url string, resp *http.Response) {
+	v := validator{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	if v.etag == "" && v.lastModified == "" {
+		delete(validators, url)
+		return
+	}
+	validators[url] = v
+}
+
+// forgetValidators drops the validators for url, forcing a full download
+// next time.
+func forgetValidators(url string) {
+	validatorsMu.Lock()
+	delete(validators, url)
+	validatorsMu.Unlock()
+}
@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"strings"
+	"sync"
+)
+
+// GitHub logins are case-insensitive, and people rename their accounts.
+// Every aggregation keys contributors by CanonicalUser so that "Foo", "foo"
+// and a former login "old-foo" all count as one person.
+
+var (
+	identityMu sync.RWMutex
+	// fileAliases maps a lowercased login to the lowercased login it
+	// should be merged into, as loaded from Config.AliasesFile.
+	fileAliases = make(map[string]string)
+	// resolvedAliases holds merges discovered via GitHub user IDs.
+	resolvedAliases = make(map[string]string)
+)
+
+// CanonicalUser returns the identity key for a GitHub login: lowercased,
+// with known aliases followed to the account's current login.
+func CanonicalUser(login string) string {
+	key := strings.ToLower(login)
+	identityMu.RLock()
+	defer identityMu.RUnlock()
+	// Follow alias chains (a -> b -> c), bounded in case of a cycle.
+	for i := 0; i < 8; i++ {
+		next, ok := fileAliases[key]
+		if !ok {
+			next, ok = resolvedAliases[key]
+		}
+		if !ok || next == key {
+			break
+		}
+		key = next
+	}
+	return key
+}
+
+// SameUser reports whether two logins belong to the same contributor.
+func SameUser(a, b string) bool {
+	return CanonicalUser(a) == CanonicalUser(b)
+}
+
+// LoadAliases replaces the file-based alias map with the JSON object in
+// path, which maps former logins to current ones:
+//
+//	{"old-login": "new-login"}
+func LoadAliases(path string) error {
+	var raw map[string]string
+	if err := readJSON(path, &raw); err != nil {
+		return err
+	}
+	SetAliases(raw)
+	return nil
+}
+
+// SetAliases replaces the file-based alias map. Keys and values are logins;
+// case is ignored.
+func SetAliases(aliases map[string]string) {
+	m := make(map[string]string, len(aliases))
+	for from, to := range aliases {
+		m[strings.ToLower(from)] = strings.ToLower(to)
+	}
+	identityMu.Lock()
+	fileAliases = m
+	identityMu.Unlock()
+	rebuildIndex()
+}
+
+// ResolveUserIDs merges contributor logins by the GitHub user ID of the
+// PRs they are credited for, which EnrichReleases records: each login whose
+// enriched PRs were all authored by one account merges into that account's
+// login as of the newest release. A login credited for PRs of several
+// accounts is left alone. It makes no API calls, so it only finds merges
+// once the PRs are enriched.
+func ResolveUserIDs() {
+	type account struct {
+		login   string
+		version Version
+	}
+	accounts := make(map[int64]account)
+	ids := make(map[string]int64) // login -> author ID, or -1 if ambiguous
+	mu.RLock()
+	for v, r := range cached {
+		for _, list := range enrichable(&r) {
+			for _, c := range *list {
+				key := strings.ToLower(c.GitHubUser)
+				for _, pr := range c.PRs {
+					if pr.AuthorID == 0 {
+						continue
+					}
+					if a, ok := accounts[pr.AuthorID]; !ok || a.version.Less(v) {
+						accounts[pr.AuthorID] = account{strings.ToLower(pr.AuthorLogin), v}
+					}
+					if id, ok := ids[key]; !ok {
+						ids[key] = pr.AuthorID
+					} else if id != pr.AuthorID {
+						ids[key] = -1
+					}
+				}
+			}
+		}
+	}
+	mu.RUnlock()
+
+	aliases := make(map[string]string)
+	for login, id := range ids {
+		if a, ok := accounts[id]; ok && a.login != login {
+			aliases[login] = a.login
+		}
+	}
+	identityMu.Lock()
+	resolvedAliases = aliases
+	identityMu.Unlock()
+	rebuildIndex()
+}
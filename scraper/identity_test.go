@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestResolveUserIDs(t *testing.T) {
+	identityMu.Lock()
+	aliases := maps.Clone(resolvedAliases)
+	identityMu.Unlock()
+	older, newer := Version{Major: 4, Minor: 1}, Version{Major: 4, Minor: 2}
+	pr := func(id int64, login string) PR {
+		return PR{Enriched: true, AuthorID: id, AuthorLogin: login}
+	}
+	mu.Lock()
+	cached[older] = Release{Version: older, Contributors: []Contributor{
+		// Account 7 was renamed twice; its PRs report the login it had
+		// when each was enriched.
+		{GitHubUser: "id-old-a", PRs: []PR{pr(7, "ID-Old-B")}},
+		{GitHubUser: "ID-Old-B", PRs: []PR{pr(7, "ID-Old-B")}},
+		{GitHubUser: "id-other", PRs: []PR{pr(8, "id-other")}},
+		// Credited for PRs of two accounts, so not merged into either.
+		{GitHubUser: "id-shared", PRs: []PR{pr(7, "ID-Old-B"), pr(8, "id-other")}},
+		{GitHubUser: "id-unenriched", PRs: []PR{{}}},
+	}}
+	cached[newer] = Release{Version: newer, Documentation: []Contributor{
+		{GitHubUser: "id-current", PRs: []PR{pr(7, "ID-Current")}},
+	}}
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(cached, older)
+		delete(cached, newer)
+		mu.Unlock()
+		identityMu.Lock()
+		resolvedAliases = aliases
+		identityMu.Unlock()
+		rebuildIndex()
+	})
+
+	ResolveUserIDs()
+	for _, login := range []string{"id-old-a", "ID-OLD-B", "id-current"} {
+		if got := CanonicalUser(login); got != "id-current" {
+			t.Errorf("CanonicalUser(%q) = %q, want id-current", login, got)
+		}
+	}
+	for _, login := range []string{"id-other", "id-shared", "id-unenriched"} {
+		if got := CanonicalUser(login); got != login {
+			t.Errorf("CanonicalUser(%q) = %q, want it unchanged", login, got)
+		}
+	}
+	index := GetIndex()
+	if h := index.Contributor("id-old-b"); h == nil || h != index.Contributor("id-current") {
+		t.Error("id-old-b and id-current have separate histories in the index")
+	}
+}
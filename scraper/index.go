@@ -0,0 +1,307 @@
+package scraper
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Index is an immutable aggregate of every cached release, keyed by
+// contributor identity. It is rebuilt whenever the cache changes and swapped
+// in atomically, so handlers read totals without rescanning every release.
+// Values returned from an Index are shared and must not be modified.
+type Index struct {
+	byUser      map[string]*ContributorHistory // CanonicalUser -> history
+	ranked      []*ContributorHistory          // by TotalPRs, then ReleaseCount, descending
+	issues      []*ContributorHistory          // issue trackers, by releases thanked
+	docs        []*ContributorHistory          // documentation contributors, by TotalDocsPRs
+	translators []*TranslatorHistory           // by ReleaseCount
+	repos       []RepoSummary                  // by PRs
+
+	releases  []Release       // newest first, by date then version
+	byVersion map[Version]int // index into releases
+
+	derivedMu sync.Mutex
+	derived   map[string]*Index // Between and ForRepo results, by query
+}
+
+// derivedLimit bounds the filtered indexes an Index memoizes, since their
+// ranges and repos come from request parameters.
+const derivedLimit = 64
+
+// derive returns the index filtered from ix by key, building it on first
+// use. Each snapshot memoizes its own, so a rebuild starts afresh.
+func (ix *Index) derive(key string, build func() *Index) *Index {
+	ix.derivedMu.Lock()
+	d, ok := ix.derived[key]
+	ix.derivedMu.Unlock()
+	if ok {
+		return d
+	}
+	d = build()
+	ix.derivedMu.Lock()
+	if ix.derived == nil || len(ix.derived) >= derivedLimit {
+		ix.derived = make(map[string]*Index)
+	}
+	ix.derived[key] = d
+	ix.derivedMu.Unlock()
+	return d
+}
+
+var (
+	currentIndex atomic.Pointer[Index]
+	// rebuildMu serializes rebuilds so a slower rebuild of an older
+	// snapshot can never overwrite a newer index.
+	rebuildMu sync.Mutex
+)
+
+// GetIndex returns the current contributor index. It is never nil.
+func GetIndex() *Index {
+	if ix := currentIndex.Load(); ix != nil {
+		return ix
+	}
+	return rebuildIndex()
+}
+
+// Contributor returns the aggregated history for login, or nil if the
+// contributor appears in no cached release.
+func (ix *Index) Contributor(login string) *ContributorHistory {
+	return ix.byUser[CanonicalUser(login)]
+}
+
+// Contributors returns every contributor ranked by total PRs, then by
+// number of releases contributed to. People thanked only for issue tracking
+// or documentation are included, with a ReleaseCount of zero.
+func (ix *Index) Contributors() []*ContributorHistory {
+	return ix.ranked
+}
+
+// IssueTrackers returns contributors thanked for issue tracking, ranked by
+// the number of releases they were thanked in.
+func (ix *Index) IssueTrackers() []*ContributorHistory {
+	return ix.issues
+}
+
+// DocsContributors returns documentation contributors ranked by PRs.
+func (ix *Index) DocsContributors() []*ContributorHistory {
+	return ix.docs
+}
+
+// Translators returns localization contributors ranked by releases.
+func (ix *Index) Translators() []*TranslatorHistory {
+	return ix.translators
+}
+
+// Releases returns the indexed releases, newest first.
+func (ix *Index) Releases() []Release {
+	return ix.releases
+}
+
+// Release returns the indexed release for version, ignoring any recovery
+// component.
+func (ix *Index) Release(version Version) (Release, bool) {
+	i, ok := ix.byVersion[version.Base()]
+	if !ok {
+		return Release{}, false
+	}
+	return ix.releases[i], true
+}
+
+// Between returns an index of only the releases that shipped in [from, to).
+// A zero from or to leaves that end open. Releases without a date are left
+// out of any bounded range. The result is memoized with ix.
+func (ix *Index) Between(from, to time.Time) *Index {
+	if from.IsZero() && to.IsZero() {
+		return ix
+	}
+	key := fmt.Sprintf("between %d %d", unixNano(from), unixNano(to))
+	return ix.derive(key, func() *Index {
+		var releases []Release
+		for _, r := range ix.releases {
+			if r.Date.IsZero() || (!from.IsZero() && r.Date.Before(from)) || (!to.IsZero() && !r.Date.Before(to)) {
+				continue
+			}
+			releases = append(releases, r)
+		}
+		return buildIndex(releases)
+	})
+}
+
+// unixNano is t.UnixNano, or 0 for the zero time.
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// IsFirstTime reports whether version is the earliest cached release the
+// contributor appears in (or they appear in none before it).
+func (ix *Index) IsFirstTime(login string, version Version) bool {
+	h := ix.Contributor(login)
+	if h == nil || h.FirstRelease.IsZero() {
+		return true
+	}
+	return !h.FirstRelease.Less(version)
+}
+
+// rebuildIndex aggregates the cache into a fresh Index and publishes it.
+// It is called whenever cached releases or identity aliases change.
+func rebuildIndex() *Index {
+	rebuildMu.Lock()
+	defer rebuildMu.Unlock()
+
+	mu.RLock()
+	releases := make([]Release, 0, len(cached))
+	for _, r := range cached {
+		releases = append(releases, r)
+	}
+	mu.RUnlock()
+
+	ix := buildIndex(releases)
+	currentIndex.Store(ix)
+	return ix
+}
+
+// compareReleases orders releases by ship date, then by version, so notes
+// without a date or sharing a month still sort sensibly.
+func compareReleases(a, b Release) int {
+	if !a.Date.IsZero() && !b.Date.IsZero() && !a.Date.Equal(b.Date) {
+		return a.Date.Compare(b.Date)
+	}
+	return a.Version.Compare(b.Version)
+}
+
+// NewIndex aggregates releases into an Index of their own, leaving the
+// cache and GetIndex alone; for tools and tests working on a fixed set.
+func NewIndex(releases []Release) *Index {
+	return buildIndex(slices.Clone(releases))
+}
+
+// buildIndex aggregates releases, which it sorts in place.
+func buildIndex(releases []Release) *Index {
+	// Newest first, so the first occurrence of a user carries their
+	// most recent login, name and avatar.
+	slices.SortFunc(releases, func(a, b Release) int {
+		return compareReleases(b, a)
+	})
+
+	ix := &Index{
+		byUser:    make(map[string]*ContributorHistory),
+		releases:  releases,
+		byVersion: make(map[Version]int, len(releases)),
+	}
+	for i, r := range releases {
+		ix.byVersion[r.Version] = i
+	}
+	history := func(c Contributor) (string, *ContributorHistory) {
+		key := CanonicalUser(c.GitHubUser)
+		h, ok := ix.byUser[key]
+		if !ok {
+			h = &ContributorHistory{
+				GitHubUser:       c.GitHubUser,
+				Name:             c.Name,
+				AvatarURL:        c.AvatarURL,
+				PRsByRelease:     make(map[Version][]PR),
+				DocsPRsByRelease: make(map[Version][]PR),
+			}
+			ix.byUser[key] = h
+			ix.ranked = append(ix.ranked, h)
+		}
+		if h.Name == "" {
+			h.Name = c.Name
+		}
+		if h.AvatarURL == "" {
+			h.AvatarURL = c.AvatarURL
+		}
+		return key, h
+	}
+	translators := make(map[string]*TranslatorHistory)
+
+	for _, r := range releases {
+		seen := make(map[string]bool)
+		for _, c := range r.Contributors {
+			key, h := history(c)
+			if h.LatestRelease.IsZero() {
+				h.LatestRelease = r.Version
+			}
+			if len(c.PRs) > 0 {
+				h.PRsByRelease[r.Version] = append(h.PRsByRelease[r.Version], c.PRs...)
+				h.TotalPRs += len(c.PRs)
+			}
+			if !seen[key] {
+				seen[key] = true
+				h.ReleaseCount++
+			}
+			h.FirstRelease = r.Version
+		}
+
+		seen = make(map[string]bool)
+		for _, c := range r.IssueTracking {
+			key, h := history(c)
+			if !seen[key] {
+				seen[key] = true
+				if len(h.IssueTrackingReleases) == 0 {
+					ix.issues = append(ix.issues, h)
+				}
+				h.IssueTrackingReleases = append(h.IssueTrackingReleases, r.Version)
+			}
+		}
+
+		for _, c := range r.Documentation {
+			_, h := history(c)
+			if len(c.PRs) == 0 {
+				continue
+			}
+			if h.TotalDocsPRs == 0 {
+				ix.docs = append(ix.docs, h)
+			}
+			h.DocsPRsByRelease[r.Version] = append(h.DocsPRsByRelease[r.Version], c.PRs...)
+			h.TotalDocsPRs += len(c.PRs)
+		}
+
+		seen = make(map[string]bool)
+		for _, t := range r.Localization {
+			key := strings.ToLower(t.Name)
+			if t.GitHubUser != "" {
+				key = "@" + CanonicalUser(t.GitHubUser)
+			}
+			th, ok := translators[key]
+			if !ok {
+				th = &TranslatorHistory{Name: t.Name, GitHubUser: t.GitHubUser, LatestRelease: r.Version}
+				translators[key] = th
+				ix.translators = append(ix.translators, th)
+			}
+			if t.Language != "" && !slices.Contains(th.Languages, t.Language) {
+				th.Languages = append(th.Languages, t.Language)
+			}
+			if !seen[key] {
+				seen[key] = true
+				th.ReleaseCount++
+			}
+		}
+	}
+
+	sort.SliceStable(ix.ranked, func(i, j int) bool {
+		a, b := ix.ranked[i], ix.ranked[j]
+		if a.TotalPRs != b.TotalPRs {
+			return a.TotalPRs > b.TotalPRs
+		}
+		return a.ReleaseCount > b.ReleaseCount
+	})
+	sort.SliceStable(ix.issues, func(i, j int) bool {
+		return len(ix.issues[i].IssueTrackingReleases) > len(ix.issues[j].IssueTrackingReleases)
+	})
+	sort.SliceStable(ix.docs, func(i, j int) bool {
+		return ix.docs[i].TotalDocsPRs > ix.docs[j].TotalDocsPRs
+	})
+	sort.SliceStable(ix.translators, func(i, j int) bool {
+		return ix.translators[i].ReleaseCount > ix.translators[j].ReleaseCount
+	})
+	ix.repos = repoSummaries(releases)
+	return ix
+}
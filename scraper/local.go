@@ -0,0 +1,69 @@
+package scraper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	localModTimesMu sync.Mutex
+	// localModTimes remembers the modification time of each file last
+	// parsed, playing the role ETags play for remote fetches.
+	localModTimes = make(map[string]time.Time) // by file path
+)
+
+// discoverLocalVersions lists the vX_YY.md files in dir, newest first.
+func discoverLocalVersions(dir string) ([]Version, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return versionsFromFileNames(names), nil
+}
+
+// fetchLocalRelease reads and parses dir/<version>.md. A file whose
+// modification time is unchanged since it was last parsed returns the
+// cached release with changed=false.
+func fetchLocalRelease(ctx context.Context, dir string, version Version) (Release, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return Release{}, false, err
+	}
+	path := filepath.Join(dir, version.ID()+".md")
+	info, err := os.Stat(path)
+	if err != nil {
+		return Release{}, false, err
+	}
+
+	mu.RLock()
+	previous, hasPrevious := cached[version]
+	mu.RUnlock()
+
+	localModTimesMu.Lock()
+	lastMod, seen := localModTimes[path]
+	localModTimesMu.Unlock()
+	if hasPrevious && seen && lastMod.Equal(info.ModTime()) {
+		fetchHits.Add(1)
+		return previous, false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Release{}, false, err
+	}
+	fetchDownloads.Add(1)
+
+	localModTimesMu.Lock()
+	localModTimes[path] = info.ModTime()
+	localModTimesMu.Unlock()
+
+	return parseMarkdown(version, string(data)), true, nil
+}
@@ -0,0 +1,493 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Release notes have been written by hand for a decade, and the "Thank you"
+// section has drifted: "### Pull Requests" vs "## Pull requests", bare
+// "Contributions to `repo`" blocks directly under "## Thank You", contributor
+// links written as "@user (Name)" or "Name (@user)", PR links pointing at
+// /issues/N, and items wrapped across several lines. parseMarkdown accepts
+// all of these and records every line it cannot interpret as a ParseWarning,
+// so incomplete releases show up in the parse report instead of silently
+// losing contributors.
+
+// parserVersion is stored with every cached release. Bump it whenever
+// parseMarkdown's output for the same notes changes, e.g. when it learns a
+// new section: cached releases parsed by another version are discarded on
+// startup and fetched and parsed again.
+const parserVersion = 1
+
+// ParseWarning is a line inside a contributions section that the parser
+// could not interpret.
+type ParseWarning struct {
+	Line   int    `json:"line"` // 1-based line number in the notes; 0 for the whole release
+	Text   string `json:"text"` // the offending line, trimmed
+	Reason string `json:"reason"`
+}
+
+// ParseReport summarizes how well one cached release was parsed.
+type ParseReport struct {
+	Version       Version
+	Contributors  int // pull request contributors
+	PRs           int
+	IssueTracking int
+	Documentation int
+	Localization  int
+	Warnings      []ParseWarning
+}
+
+// Incomplete reports whether the parser skipped anything in the release.
+func (r ParseReport) Incomplete() bool {
+	return len(r.Warnings) > 0
+}
+
+// GetParseReports returns a report for every cached release, newest first.
+func GetParseReports() []ParseReport {
+	mu.RLock()
+	reports := make([]ParseReport, 0, len(cached))
+	for _, r := range cached {
+		rep := ParseReport{
+			Version:       r.Version,
+			Contributors:  len(r.Contributors),
+			IssueTracking: len(r.IssueTracking),
+			Documentation: len(r.Documentation),
+			Localization:  len(r.Localization),
+			Warnings:      r.Warnings,
+		}
+		for _, c := range r.Contributors {
+			rep.PRs += len(c.PRs)
+		}
+		reports = append(reports, rep)
+	}
+	mu.RUnlock()
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[j].Version.Less(reports[i].Version)
+	})
+	return reports
+}
+
+// releaseDateLayouts are the date formats seen in release-notes front matter.
+var releaseDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"1/2/2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+}
+
+// releaseDate returns when the notes say the release shipped. Notes without
+// a front matter date are dated by the month in their title, and monthOnly
+// is set; the zero time means the notes give neither.
+func releaseDate(md string) (date time.Time, monthOnly bool) {
+	if m := releaseDateRe.FindStringSubmatch(frontMatter(md)); m != nil {
+		value := strings.Trim(m[1], `"'`)
+		for _, layout := range releaseDateLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.UTC(), false
+			}
+		}
+	}
+	if m := titleMonthRe.FindStringSubmatch(md); m != nil {
+		if t, err := time.Parse("January 2006", m[1]); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// frontMatter returns the block between the leading "---" lines of md, or
+// "" if the notes have none.
+func frontMatter(md string) string {
+	rest, ok := strings.CutPrefix(strings.ReplaceAll(md, "\r\n", "\n"), "---\n")
+	if !ok {
+		return ""
+	}
+	if end := strings.Index(rest, "\n---"); end >= 0 {
+		return rest[:end]
+	}
+	return ""
+}
+
+// Regex patterns for markdown parsing.
+var (
+	// Matches the front matter field naming the current download,
+	// "DownloadVersion: 1.109.2".
+	downloadVersionRe = regexp.MustCompile(`(?m)^DownloadVersion:\s*(\S+)\s*$`)
+	// Matches the front matter release date, "Date: 2026-02-04" or
+	// "ReleaseDate: 2/4/2026".
+	releaseDateRe = regexp.MustCompile(`(?m)^(?:Release)?Date:\s*(.*?)\s*$`)
+	// Matches the month a release is named after, in the front matter title
+	// or the first heading: "TOCTitle: January 2026", "# January 2026 (version 1.109)".
+	titleMonthRe = regexp.MustCompile(`(?m)^(?:TOCTitle:\s*|#\s+)((?:January|February|March|April|May|June|July|August|September|October|November|December) \d{4})\b`)
+	// Matches any ATX heading: "### Pull Requests".
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)[\s#]*$`)
+	// Headings that open a pull request section.
+	prHeadingRe = regexp.MustCompile(`(?i)^pull requests?\b`)
+	// Headings inside the acknowledgements section for non-PR contributions.
+	issuesHeadingRe       = regexp.MustCompile(`(?i)^issue tracking\b`)
+	docsHeadingRe         = regexp.MustCompile(`(?i)^(?:documentation|docs)\b`)
+	localizationHeadingRe = regexp.MustCompile(`(?i)^(?:localization|translations?)\b`)
+	// Headings that open the acknowledgements section.
+	thanksHeadingRe = regexp.MustCompile(`(?i)^thank you\b`)
+	// Matches: * [@username (Display Name)](https://github.com/username)...
+	// as well as [Display Name (@username)] and [@username], with any list marker.
+	contribLineRe = regexp.MustCompile(`^[*+-]\s+\[([^\]]+)\]\(\s*https?://(?:www\.)?github\.com/([A-Za-z0-9-]+)/?\s*\)(.*)$`)
+	// Matches indented sub-items
+	subItemRe = regexp.MustCompile(`^\s+[*+-]\s+(.+)$`)
+	// Matches any list item, indented or not.
+	bulletRe = regexp.MustCompile(`^\s*[*+-]\s`)
+	// Matches PR links: [PR #123](https://github.com/org/repo/pull/123), also
+	// [#123], [Pull request #123], /issues/123 and trailing /files or anchors.
+	prLinkRe = regexp.MustCompile(`\[(?:PR|[Pp]ull [Rr]equest)?\s*#?(\d+)\]\((https?://github\.com/([\w.-]+/[\w.-]+)/(?:pull|issues)/(\d+))[^)\s]*\)`)
+	// Matches bare PR URLs not wrapped in a markdown link.
+	bareLinkRe = regexp.MustCompile(`(?:^|[^(\w/])(https?://github\.com/([\w.-]+/[\w.-]+)/(?:pull|issues)/(\d+))`)
+	// Matches repo section headers: Contributions to `repo`: with or without
+	// the colon, backticks or a heading marker.
+	repoSectionRe = regexp.MustCompile("(?i)^(?:#{1,6}\\s+)?Contributions to\\s+`?([\\w./-]+)`?\\s*:?\\s*$")
+	// Matches any other "Contributions to ..." line, e.g. issue tracking.
+	otherContribRe = regexp.MustCompile(`(?i)^(?:#{1,6}\s+)?Contributions to\b`)
+	issueContribRe = regexp.MustCompile(`(?i)\bissue`)
+	// Matches translator lists: * **French:** Antoine Griffard, Thierry D.
+	languageLineRe = regexp.MustCompile(`^[*+-]\s+\*\*([^*]+?):?\*\*:?\s*(.+)$`)
+)
+
+// noteLine is a logical line of the notes: a physical line plus any
+// continuation lines folded into it.
+type noteLine struct {
+	num  int
+	text string
+	cont []string // continuation lines, folded into text by logicalLines
+}
+
+// logicalLines splits md into lines, folding wrapped list items into the
+// item they continue.
+func logicalLines(md string) []noteLine {
+	var (
+		out    []noteLine
+		inItem bool // the last logical line is a list item
+	)
+	for i, line := range strings.Split(md, "\n") {
+		line = strings.TrimRight(line, "\r \t")
+		trimmed := strings.TrimSpace(line)
+		isBullet := bulletRe.MatchString(line)
+		if inItem && trimmed != "" && !isBullet && !strings.HasPrefix(trimmed, "#") &&
+			!strings.HasPrefix(trimmed, "<") && !repoSectionRe.MatchString(trimmed) {
+			out[len(out)-1].cont = append(out[len(out)-1].cont, trimmed)
+			continue
+		}
+		out = append(out, noteLine{num: i + 1, text: line})
+		inItem = isBullet
+	}
+	for i := range out {
+		if out[i].cont != nil {
+			out[i].text += " " + strings.Join(out[i].cont, " ")
+			out[i].cont = nil
+		}
+	}
+	return out
+}
+
+// section is the kind of list the parser is currently reading.
+type section int
+
+const (
+	sectionNone section = iota
+	sectionPRs
+	sectionIssues
+	sectionDocs
+	sectionLocalization
+)
+
+// sectionFor maps a heading inside "Thank you" to the list it introduces.
+func sectionFor(title string) section {
+	switch {
+	case prHeadingRe.MatchString(title):
+		return sectionPRs
+	case issuesHeadingRe.MatchString(title):
+		return sectionIssues
+	case docsHeadingRe.MatchString(title):
+		return sectionDocs
+	case localizationHeadingRe.MatchString(title):
+		return sectionLocalization
+	}
+	return sectionNone
+}
+
+func parseMarkdown(version Version, md string) Release {
+	release := Release{
+		Version:     version,
+		DisplayName: version.String(),
+	}
+	// Recovery releases update the notes' DownloadVersion in place.
+	if m := downloadVersionRe.FindStringSubmatch(md); m != nil {
+		if v, err := ParseVersion(m[1]); err == nil && v.IsRecovery() && v.Base() == version.Base() {
+			release.Recovery = v
+		}
+	}
+	release.Date, release.DateMonthOnly = releaseDate(md)
+	warn := func(l noteLine, reason string) {
+		release.Warnings = append(release.Warnings, ParseWarning{
+			Line:   l.num,
+			Text:   strings.TrimSpace(l.text),
+			Reason: reason,
+		})
+	}
+
+	var (
+		// current is the list being read; sectionLevel is the heading level
+		// that opened it, and implicit marks a list opened by a bare
+		// "Contributions to" line under "Thank you", which any heading ends.
+		current      section
+		sectionLevel int
+		implicit     bool
+		// thanksLevel is the level of the enclosing "Thank you" heading,
+		// or 0; thanksSub is set once it has any subsection heading.
+		thanksLevel int
+		thanksSub   bool
+		// otherBlock is set after an unknown "Contributions to" line,
+		// whose list is not pull requests.
+		otherBlock bool
+		sawSection bool
+
+		currentContrib *Contributor
+		contribLines   = make(map[*[]Contributor][]noteLine)
+	)
+	// openImplicit starts a list directly under "Thank you", as the older
+	// notes without subsection headings do.
+	openImplicit := func(s section) {
+		current, implicit, sectionLevel = s, true, thanksLevel
+		sawSection = true
+	}
+	canOpenImplicit := func() bool {
+		return thanksLevel > 0 && !thanksSub && (current == sectionNone || implicit)
+	}
+
+	for _, l := range logicalLines(md) {
+		line := l.text
+		trimmed := strings.TrimSpace(line)
+
+		// Repo section header (informational, we get repo from PR URL).
+		// Older notes have no "Pull Requests" heading and list these
+		// directly under "Thank you".
+		if m := repoSectionRe.FindStringSubmatch(trimmed); m != nil {
+			isLocalization := strings.EqualFold(m[1], "localization")
+			switch {
+			case canOpenImplicit() && isLocalization:
+				openImplicit(sectionLocalization)
+			case canOpenImplicit():
+				openImplicit(sectionPRs)
+			case current != sectionNone && isLocalization:
+				current = sectionLocalization
+			}
+			otherBlock = false
+			currentContrib = nil
+			continue
+		}
+		// "Contributions to our issue tracking:" and the like.
+		if otherContribRe.MatchString(trimmed) {
+			switch {
+			case issueContribRe.MatchString(trimmed) && canOpenImplicit():
+				openImplicit(sectionIssues)
+			case implicit:
+				current = sectionNone
+				otherBlock = true
+			}
+			currentContrib = nil
+			continue
+		}
+		// The oldest notes list contributors straight under "Thank you".
+		if current == sectionNone && thanksLevel > 0 && !thanksSub && !otherBlock && contribLineRe.MatchString(line) {
+			openImplicit(sectionPRs)
+		}
+
+		if m := headingRe.FindStringSubmatch(trimmed); m != nil {
+			level, title := len(m[1]), m[2]
+			if current != sectionNone && (level <= sectionLevel || implicit) {
+				current = sectionNone
+			}
+			if thanksLevel > 0 {
+				if level <= thanksLevel {
+					thanksLevel = 0
+				} else {
+					thanksSub = true
+				}
+			}
+			switch s := sectionFor(title); {
+			case s == sectionPRs, s != sectionNone && thanksLevel > 0:
+				// Only pull requests are recognized outside "Thank you";
+				// elsewhere "## Documentation" is a feature section.
+				current, implicit, sectionLevel = s, false, level
+				sawSection = true
+			case thanksHeadingRe.MatchString(title):
+				thanksLevel, thanksSub, otherBlock = level, false, false
+				sawSection = true
+			}
+			currentContrib = nil
+			continue
+		}
+
+		if current == sectionNone || trimmed == "" || strings.HasPrefix(trimmed, "<") || strings.Trim(trimmed, "-*_") == "" {
+			continue
+		}
+
+		switch current {
+		case sectionIssues:
+			if m := contribLineRe.FindStringSubmatch(line); m != nil {
+				release.IssueTracking = append(release.IssueTracking, newContributor(m[1], m[2]))
+				continue
+			}
+		case sectionLocalization:
+			if m := contribLineRe.FindStringSubmatch(line); m != nil {
+				c := newContributor(m[1], m[2])
+				release.Localization = append(release.Localization, Translator{Name: c.Name, GitHubUser: c.GitHubUser})
+				continue
+			}
+			if m := languageLineRe.FindStringSubmatch(line); m != nil {
+				for _, name := range strings.Split(m[2], ",") {
+					name = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(name), "."))
+					if name != "" {
+						release.Localization = append(release.Localization, Translator{Name: name, Language: strings.TrimSpace(m[1])})
+					}
+				}
+				continue
+			}
+			if !bulletRe.MatchString(line) {
+				// Introductory prose about the localization community.
+				continue
+			}
+		default:
+			list := &release.Contributors
+			if current == sectionDocs {
+				list = &release.Documentation
+			}
+
+			// Contributor line
+			if m := contribLineRe.FindStringSubmatch(line); m != nil {
+				c := newContributor(m[1], m[2])
+				c.PRs = prsIn(m[3])
+				*list = append(*list, c)
+				contribLines[list] = append(contribLines[list], l)
+				currentContrib = &(*list)[len(*list)-1]
+				continue
+			}
+
+			// Sub-item (belongs to current contributor)
+			if m := subItemRe.FindStringSubmatch(line); m != nil {
+				if currentContrib == nil {
+					warn(l, "item outside a contributor")
+					continue
+				}
+				prs := prsIn(m[1])
+				if len(prs) == 0 {
+					warn(l, "no pull request link")
+					continue
+				}
+				currentContrib.PRs = append(currentContrib.PRs, prs...)
+				continue
+			}
+		}
+
+		if bulletRe.MatchString(line) {
+			// Don't attribute its sub-items to the previous contributor.
+			currentContrib = nil
+			warn(l, "unrecognized contributor line")
+		} else {
+			warn(l, "unrecognized line")
+		}
+	}
+
+	for _, list := range []*[]Contributor{&release.Contributors, &release.Documentation} {
+		for i, c := range *list {
+			if len(c.PRs) == 0 {
+				warn(contribLines[list][i], "contributor without pull requests")
+			}
+		}
+	}
+	sort.SliceStable(release.Warnings, func(i, j int) bool {
+		return release.Warnings[i].Line < release.Warnings[j].Line
+	})
+	if sawSection && len(release.Contributors)+len(release.IssueTracking)+len(release.Documentation)+len(release.Localization) == 0 {
+		release.Warnings = append(release.Warnings, ParseWarning{Reason: "no contributors found"})
+	}
+
+	return release
+}
+
+// newContributor builds a Contributor from a contributor link's text and login.
+func newContributor(text, githubUser string) Contributor {
+	return Contributor{
+		Name:       contributorName(text, githubUser),
+		GitHubUser: githubUser,
+		AvatarURL:  fmt.Sprintf("https://github.com/%s.png?size=80", githubUser),
+	}
+}
+
+// contributorName picks the display name out of a contributor link's text,
+// which is "@user (Name)", "Name (@user)", "@user" or just "Name".
+func contributorName(text, login string) string {
+	text = strings.TrimSpace(text)
+	if open := strings.Index(text, " ("); open != -1 && strings.HasSuffix(text, ")") {
+		before := strings.TrimSpace(text[:open])
+		inner := strings.TrimSpace(text[open+2 : len(text)-1])
+		switch {
+		case strings.HasPrefix(before, "@") && inner != "":
+			return inner
+		case strings.HasPrefix(inner, "@") && before != "":
+			return before
+		}
+	}
+	if text == "" || strings.HasPrefix(text, "@") {
+		return login
+	}
+	return text
+}
+
+// prsIn extracts every PR linked from text, preferring markdown links and
+// falling back to bare URLs. Several links on one item share the
+// description in front of the first.
+func prsIn(text string) []PR {
+	var prs []PR
+	matches := prLinkRe.FindAllStringSubmatch(text, -1)
+	for _, m := range matches {
+		prs = append(prs, PR{
+			Title:  extractDescription(text, matches[0][0]),
+			URL:    m[2],
+			Repo:   m[3],
+			Number: m[4],
+		})
+	}
+	if len(prs) > 0 {
+		return prs
+	}
+	bare := bareLinkRe.FindAllStringSubmatch(text, -1)
+	for _, m := range bare {
+		prs = append(prs, PR{
+			Title:  extractDescription(text, bare[0][1]),
+			URL:    m[1],
+			Repo:   m[2],
+			Number: m[3],
+		})
+	}
+	return prs
+}
+
+func extractDescription(text, prLink string) string {
+	idx := strings.Index(text, prLink)
+	if idx <= 0 {
+		return ""
+	}
+	desc := strings.TrimSpace(text[:idx])
+	desc = strings.TrimPrefix(desc, ": ")
+	desc = strings.TrimPrefix(desc, ":")
+	// "Fix the thing ([PR #1](...))" leaves a dangling parenthesis.
+	desc = strings.TrimRight(desc, " (,")
+	desc = strings.TrimSpace(desc)
+	return desc
+}
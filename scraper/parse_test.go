@@ -0,0 +1,550 @@
+package scraper
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+//go:generate go run gengolden.go
+//go:generate go test -run TestParseMarkdownGolden -update
+
+// Run "go test ./scraper -run Golden -update" after an intended parser
+// change, and review the diff of testdata/golden/*.json. "go generate
+// ./scraper" downloads the snapshots afresh from microsoft/vscode-docs first.
+var update = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// TestParseMarkdownGolden parses every testdata/golden/vX_YY.md snapshot,
+// a trimmed copy of the real release notes, and compares the result with
+// the Release JSON stored next to it.
+func TestParseMarkdownGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "golden", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Skip(`no snapshots in testdata/golden; run "go generate ./scraper" to download them`)
+	}
+	for _, path := range files {
+		name := strings.TrimSuffix(filepath.Base(path), ".md")
+		t.Run(name, func(t *testing.T) {
+			version, err := ParseVersion(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			md, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(parseMarkdown(version, string(md)), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(path, ".md") + ".json"
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("parse of %s differs from %s (run with -update to accept):\n%s", path, golden, got)
+			}
+		})
+	}
+}
+
+// parseFormatTests are small synthetic notes in each layout the release
+// notes have used, plus lines the parser must warn about.
+var parseFormatTests = []struct {
+	name    string
+	version string
+	md      string
+	want    string // summarizeRelease of the parse
+}{
+	{
+		name:    "flat list under thank you",
+		version: "v1_17",
+		md: `# September 2017 (version 1.17)
+
+## Notable Changes
+
+* [35276](https://github.com/microsoft/vscode/issues/35276): Not a contributor
+
+## Thank You
+
+Last but certainly not least, a big *__Thank You!__* to the following folks:
+
+* [A (@a)](https://github.com/a): Fix a typo [PR #1](https://github.com/Microsoft/vscode/pull/1)
+* [B (@b)](https://github.com/b): Fix links to the
+  settings documentation [PR #2](https://github.com/Microsoft/vscode/pull/2)
+* [C (@c)](https://github.com/c)
+  * Add a file extension [PR #3](https://github.com/Microsoft/vscode/pull/3)
+  * Debug it ([PR #4](https://github.com/Microsoft/vscode/pull/4))
+
+Contributions to ` + "`vscode-node-debug`" + `:
+
+* [D (@d)](https://github.com/d): Support a flag [PR #5](https://github.com/Microsoft/vscode-node-debug/pull/5/files)
+
+Contributions to ` + "`language-server-protocol`" + `
+
+* [E (@e)](https://github.com/e): Fix typo [#6](https://github.com/Microsoft/language-server-protocol/issues/6)
+
+Contributions to ` + "`vscode-tslint`" + `:
+
+- [@f](https://github.com/f/): Support workspaces
+https://github.com/Microsoft/vscode-tslint/pull/7
+
+<a id="scroll-to-top" role="button" href="#"><span class="icon"></span></a>
+`,
+		want: "contributors a[Fix a typo] b[Fix links to the settings documentation] c[Add a file extension, Debug it] d[Support a flag] e[Fix typo] f[Support workspaces]",
+	},
+	{
+		name:    "contributions to paragraphs",
+		version: "v1_35",
+		md: `---
+DownloadVersion: 1.35.1
+---
+# May 2019 (version 1.35)
+
+## Thank you
+
+Contributions to our issue tracking:
+
+* [A (@a)](https://github.com/a)
+* [B (@b)](https://github.com/b)
+
+Contributions to ` + "`vscode`" + `:
+
+* [C (@c)](https://github.com/c)
+  * Fix a keybinding [PR #1](https://github.com/microsoft/vscode/pull/1)
+  * Add a setting [PR #2](https://github.com/microsoft/vscode/pull/2)
+* [@d (D)](https://github.com/d): Fix flicker [PR #3](https://github.com/microsoft/vscode/pull/3)
+
+Contributions to ` + "`vscode-eslint`" + `:
+
+* [E (@e)](https://github.com/e): Honor a setting [PR #4](https://github.com/microsoft/vscode-eslint/pull/4)
+
+Contributions to ` + "`localization`" + `:
+
+There are over 800 members in the Transifex team.
+
+* **French:** F One, F Two.
+`,
+		want: "contributors c[Fix a keybinding, Add a setting] d[Fix flicker] e[Honor a setting]; issue tracking a b; localization 2",
+	},
+	{
+		name:    "subsections",
+		version: "v1_60",
+		md: `# August 2021 (version 1.60)
+
+## Thank you
+
+### Issue tracking
+
+Contributions to our issue tracking:
+
+- [@a (A)](https://github.com/a)
+
+### Pull requests
+
+Contributions to ` + "`vscode`" + `:
+
+- [@b (B)](https://github.com/b): Add a title [PR #1](https://github.com/microsoft/vscode/pull/1)
+- [@c (C)](https://github.com/c)
+  - Fix scrolling [PR #2](https://github.com/microsoft/vscode/pull/2)
+
+### Documentation
+
+Contributions to ` + "`vscode-docs`" + `:
+
+- [@d (D)](https://github.com/d): Fix a link [PR #3](https://github.com/microsoft/vscode-docs/pull/3)
+`,
+		want: "contributors b[Add a title] c[Fix scrolling]; issue tracking a; documentation d[Fix a link]",
+	},
+	{
+		name:    "current",
+		version: "v1_109",
+		md: `# January 2026 (version 1.109)
+
+## Thank you
+
+### Pull Requests
+
+Contributions to ` + "`vscode`" + `:
+
+* [@a (A)](https://github.com/a): Two fixes [PR #1](https://github.com/microsoft/vscode/pull/1), [PR #2](https://github.com/microsoft/vscode/pull/2)
+
+## Notable fixes
+
+* [@z (Z)](https://github.com/z): Outside the thank you section [PR #3](https://github.com/microsoft/vscode/pull/3)
+`,
+		want: "contributors a[Two fixes, Two fixes]",
+	},
+	{
+		name:    "warnings",
+		version: "v1_80",
+		md: `# June 2023 (version 1.80)
+
+## Thank you
+
+### Pull Requests
+
+Contributions to ` + "`vscode`" + `:
+
+* [@a (A)](https://github.com/a): Works [PR #1](https://github.com/microsoft/vscode/pull/1)
+* [@b (B)](https://github.com/b)
+  * Talked about it in the issue
+* A bullet without a profile link [PR #2](https://github.com/microsoft/vscode/pull/2)
+  * An orphaned sub-item [PR #3](https://github.com/microsoft/vscode/pull/3)
+
+A stray paragraph inside the section.
+`,
+		want: "contributors a[Works] b[]; warnings 10:contributor without pull requests 11:no pull request link 12:unrecognized contributor line 13:item outside a contributor 15:unrecognized line",
+	},
+	{
+		name:    "empty",
+		version: "v1_81",
+		md:      "# July 2023 (version 1.81)\n\n## Thank you\n\n### Pull Requests\n\nA paragraph but no list.\n",
+		want:    "warnings 7:unrecognized line 0:no contributors found",
+	},
+}
+
+// summarizeRelease lists who a parse found and what it warned about.
+func summarizeRelease(r Release) string {
+	list := func(cs []Contributor) string {
+		var parts []string
+		for _, c := range cs {
+			var titles []string
+			for _, pr := range c.PRs {
+				titles = append(titles, pr.Title)
+			}
+			parts = append(parts, c.GitHubUser+"["+strings.Join(titles, ", ")+"]")
+		}
+		return strings.Join(parts, " ")
+	}
+	var parts []string
+	if len(r.Contributors) > 0 {
+		parts = append(parts, "contributors "+list(r.Contributors))
+	}
+	if len(r.IssueTracking) > 0 {
+		var logins []string
+		for _, c := range r.IssueTracking {
+			logins = append(logins, c.GitHubUser)
+		}
+		parts = append(parts, "issue tracking "+strings.Join(logins, " "))
+	}
+	if len(r.Documentation) > 0 {
+		parts = append(parts, "documentation "+list(r.Documentation))
+	}
+	if len(r.Localization) > 0 {
+		parts = append(parts, fmt.Sprint("localization ", len(r.Localization)))
+	}
+	if len(r.Warnings) > 0 {
+		var warnings []string
+		for _, w := range r.Warnings {
+			warnings = append(warnings, fmt.Sprintf("%d:%s", w.Line, w.Reason))
+		}
+		parts = append(parts, "warnings "+strings.Join(warnings, " "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func TestParseMarkdownFormats(t *testing.T) {
+	for _, tt := range parseFormatTests {
+		version, err := ParseVersion(tt.version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := summarizeRelease(parseMarkdown(version, tt.md)); got != tt.want {
+			t.Errorf("%s:\n got %s\nwant %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReleaseDate(t *testing.T) {
+	tests := []struct {
+		md        string
+		want      string
+		monthOnly bool
+	}{
+		{"---\nTOCTitle: January 2026\nDate: 2026-02-04\n---\n# January 2026 (version 1.109)\n", "2026-02-04", false},
+		{"---\nReleaseDate: \"2/4/2026\"\n---\n", "2026-02-04", false},
+		{"---\nTOCTitle: May 2019\n---\n# May 2019 (version 1.35)\n", "2019-05-01", true},
+		{"# June 2023 (version 1.80)\n\nDate: 2020-01-01\n", "2023-06-01", true},
+		{"---\nDate: soon\n---\nNo title\n", "", false},
+	}
+	for _, tt := range tests {
+		date, monthOnly := releaseDate(tt.md)
+		got := ""
+		if !date.IsZero() {
+			got = date.Format("2006-01-02")
+		}
+		if got != tt.want || monthOnly != tt.monthOnly {
+			t.Errorf("releaseDate(%q) = %s, %v, want %s, %v", tt.md, got, monthOnly, tt.want, tt.monthOnly)
+		}
+	}
+}
+
+func TestExtractDescription(t *testing.T) {
+	link := "[PR #1](https://github.com/microsoft/vscode/pull/1)"
+	tests := []struct {
+		text, want string
+	}{
+		{": Fix the thing " + link, "Fix the thing"},
+		{"Fix the thing " + link, "Fix the thing"},
+		{"Fix the thing (" + link + ")", "Fix the thing"},
+		{link, ""},
+		{"no link here", ""},
+	}
+	for _, tt := range tests {
+		if got := extractDescription(tt.text, link); got != tt.want {
+			t.Errorf("extractDescription(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestContributorName(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"@alice (Alice Smith)", "Alice Smith"},
+		{"Alice Smith (@alice)", "Alice Smith"},
+		{"@alice", "alice"},
+		{"Alice", "Alice"},
+		{"", "alice"},
+	}
+	for _, tt := range tests {
+		if got := contributorName(tt.text, "alice"); got != tt.want {
+			t.Errorf("contributorName(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLogicalLines(t *testing.T) {
+	md := "* [@a](https://github.com/a): Fix the\n  wrapped\n  description [PR #1](https://github.com/o/r/pull/1)\n" +
+		"* [@b](https://github.com/b)\n" +
+		"  * sub-item\n" +
+		"Contributions to `vscode`:\n" +
+		"## Heading\n" +
+		"Paragraph\n" +
+		"continued"
+	var got []string
+	for _, l := range logicalLines(md) {
+		got = append(got, fmt.Sprintf("%d:%s", l.num, l.text))
+	}
+	want := []string{
+		"1:* [@a](https://github.com/a): Fix the wrapped description [PR #1](https://github.com/o/r/pull/1)",
+		"4:* [@b](https://github.com/b)",
+		"5:  * sub-item",
+		"6:Contributions to `vscode`:",
+		"7:## Heading",
+		"8:Paragraph",
+		"9:continued",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("logicalLines =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// An item wrapped over many lines is joined once, not line by line.
+	long := "* [@a](https://github.com/a):" + strings.Repeat("\n  word", 10000)
+	if lines := logicalLines(long); len(lines) != 1 || strings.Count(lines[0].text, "word") != 10000 {
+		t.Errorf("long item folded into %d lines", len(lines))
+	}
+}
+
+func TestPRsInSharedDescription(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Two fixes [PR #1](https://github.com/o/r/pull/1), [PR #2](https://github.com/o/r/pull/2)", "1 Two fixes; 2 Two fixes"},
+		{"Two fixes https://github.com/o/r/pull/1 https://github.com/o/r/pull/2", "1 Two fixes; 2 Two fixes"},
+		{"[PR #1](https://github.com/o/r/pull/1)", "1 "},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, pr := range prsIn(tt.text) {
+			got = append(got, pr.Number+" "+pr.Title)
+		}
+		if strings.Join(got, "; ") != tt.want {
+			t.Errorf("prsIn(%q) = %q, want %q", tt.text, strings.Join(got, "; "), tt.want)
+		}
+	}
+}
+
+func TestParseMarkdownOtherContributions(t *testing.T) {
+	// Lists after a "Contributions to" line that names no repository, other
+	// than issue tracking, are not pull requests; the next repo resumes them.
+	md := `## Thank you
+
+* [@a (A)](https://github.com/a): Fix [PR #1](https://github.com/o/r/pull/1)
+
+Contributions to our extension authoring:
+
+* [@b (B)](https://github.com/b)
+
+Contributions to ` + "`vscode`" + `:
+
+* [@c (C)](https://github.com/c): Fix [PR #2](https://github.com/o/r/pull/2)
+`
+	if got := summarizeRelease(parseMarkdown(Version{Major: 1, Minor: 20}, md)); got != "contributors a[Fix] c[Fix]" {
+		t.Errorf("parse = %q", got)
+	}
+}
+
+func TestParseMarkdownUnrecognizedItem(t *testing.T) {
+	// The sub-items of an unrecognized bullet are not credited to the
+	// contributor before it, and warnings come out in line order.
+	md := `## Thank you
+
+### Pull requests
+
+* [@b (B)](https://github.com/b)
+* [@a (A)](https://github.com/a): Fix [PR #1](https://github.com/o/r/pull/1)
+* Someone without a profile link
+  * Their fix [PR #2](https://github.com/o/r/pull/2)
+`
+	got := summarizeRelease(parseMarkdown(Version{Major: 1, Minor: 20}, md))
+	want := "contributors b[] a[Fix]; warnings 5:contributor without pull requests 7:unrecognized contributor line 8:item outside a contributor"
+	if got != want {
+		t.Errorf("parse = %q, want %q", got, want)
+	}
+}
+
+func TestRegexes(t *testing.T) {
+	tests := []struct {
+		name string
+		re   *regexp.Regexp
+		line string
+		want []string // submatches after the full match; nil for no match
+	}{
+		{"contributor", contribLineRe, "* [@a (A)](https://github.com/a): x", []string{"@a (A)", "a", ": x"}},
+		{"contributor dash", contribLineRe, "- [A (@a)](https://github.com/a/)", []string{"A (@a)", "a", ""}},
+		{"contributor no link", contribLineRe, "* A wrote things", nil},
+		{"sub-item", subItemRe, "  * Fix", []string{"Fix"}},
+		{"sub-item top level", subItemRe, "* Fix", nil},
+		{"pr link", prLinkRe, "[PR #12](https://github.com/o/r/pull/12)", []string{"12", "https://github.com/o/r/pull/12", "o/r", "12"}},
+		{"issue link", prLinkRe, "[#12](https://github.com/o/r/issues/12/files)", []string{"12", "https://github.com/o/r/issues/12", "o/r", "12"}},
+		{"repo header", repoSectionRe, "Contributions to `vscode`:", []string{"vscode"}},
+		{"repo header bare", repoSectionRe, "### Contributions to vscode-docs", []string{"vscode-docs"}},
+		{"issue tracking", repoSectionRe, "Contributions to our issue tracking:", nil},
+	}
+	for _, tt := range tests {
+		m := tt.re.FindStringSubmatch(tt.line)
+		if tt.want == nil {
+			if m != nil {
+				t.Errorf("%s: %q matched %q, want no match", tt.name, tt.line, m)
+			}
+			continue
+		}
+		if m == nil {
+			t.Errorf("%s: %q did not match", tt.name, tt.line)
+			continue
+		}
+		if got := m[1:]; strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+			t.Errorf("%s: %q submatches = %q, want %q", tt.name, tt.line, got, tt.want)
+		}
+	}
+}
+
+var digitsRe = regexp.MustCompile(`^\d+$`)
+
+// FuzzParseMarkdown checks that the parser never panics and that whatever it
+// returns is usable by the rest of the site. The seeds are whole release
+// notes, the golden snapshots and parseFormatTests, so minimizing them is slow; fuzz with -fuzzminimizetime=0:
+//
+//	go test ./scraper -run '^$' -fuzz '^FuzzParseMarkdown$' -fuzzminimizetime=0
+func FuzzParseMarkdown(f *testing.F) {
+	files, _ := filepath.Glob(filepath.Join("testdata", "golden", "*.md"))
+	for _, path := range files {
+		if md, err := os.ReadFile(path); err == nil {
+			f.Add(string(md))
+		}
+	}
+	for _, tt := range parseFormatTests {
+		f.Add(tt.md)
+	}
+	f.Add("## Thank you\n* [@a](https://github.com/a): x [PR #1](https://github.com/o/r/pull/1)\n")
+
+	f.Fuzz(func(t *testing.T, md string) {
+		r := parseMarkdown(Version{Major: 1, Minor: 2}, md)
+		if r.Version != (Version{Major: 1, Minor: 2}) || r.DisplayName != "1.2" {
+			t.Fatalf("version = %v, display = %q", r.Version, r.DisplayName)
+		}
+		if !r.Recovery.IsZero() && r.Recovery.Base() != r.Version {
+			t.Errorf("recovery %v is not a recovery of %v", r.Recovery, r.Version)
+		}
+		lines := strings.Count(md, "\n") + 1
+		for _, c := range r.Contributors {
+			if c.GitHubUser == "" || c.Name == "" {
+				t.Errorf("contributor without login or name: %+v", c)
+			}
+			for _, pr := range c.PRs {
+				if !digitsRe.MatchString(pr.Number) {
+					t.Errorf("PR number %q is not numeric", pr.Number)
+				}
+				if pr.URL == "" || !strings.HasSuffix(pr.URL, "/"+pr.Number) {
+					t.Errorf("PR URL %q does not end in its number %q", pr.URL, pr.Number)
+				}
+				if !strings.Contains(pr.URL, "/"+pr.Repo+"/") {
+					t.Errorf("PR URL %q does not contain its repo %q", pr.URL, pr.Repo)
+				}
+			}
+		}
+		for _, w := range r.Warnings {
+			if w.Reason == "" || w.Line < 0 || w.Line > lines {
+				t.Errorf("bad warning %+v for %d lines", w, lines)
+			}
+		}
+	})
+}
+
+// FuzzExtractDescription checks the description is always a trimmed piece
+// of the text before the link.
+func FuzzExtractDescription(f *testing.F) {
+	f.Add(": Fix the thing [PR #1](https://github.com/o/r/pull/1)", "[PR #1](https://github.com/o/r/pull/1)")
+	f.Add("x", "")
+	f.Fuzz(func(t *testing.T, text, link string) {
+		got := extractDescription(text, link)
+		if got != strings.TrimSpace(got) {
+			t.Errorf("description %q is not trimmed", got)
+		}
+		if !strings.Contains(text, got) {
+			t.Errorf("description %q is not part of %q", got, text)
+		}
+	})
+}
+
+// FuzzRegexes checks the submatches the parser relies on are well formed.
+func FuzzRegexes(f *testing.F) {
+	f.Add("* [@a (A)](https://github.com/a): x [PR #1](https://github.com/o/r/pull/1)")
+	f.Add("Contributions to `vscode`:")
+	f.Fuzz(func(t *testing.T, line string) {
+		if m := contribLineRe.FindStringSubmatch(line); m != nil {
+			if m[2] == "" || strings.Contains(m[2], "/") {
+				t.Errorf("contributor login %q from %q", m[2], line)
+			}
+		}
+		for _, m := range prLinkRe.FindAllStringSubmatch(line, -1) {
+			if !digitsRe.MatchString(m[4]) || !strings.HasSuffix(m[2], "/"+m[4]) || strings.Count(m[3], "/") != 1 {
+				t.Errorf("PR link submatches %q from %q", m, line)
+			}
+		}
+		if m := repoSectionRe.FindStringSubmatch(line); m != nil && m[1] == "" {
+			t.Errorf("empty repo from %q", line)
+		}
+	})
+}
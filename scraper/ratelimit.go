@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit is the most recent GitHub API rate-limit state seen by the scraper.
+type RateLimit struct {
+	Authenticated bool
+	Limit         int
+	Remaining     int
+	Reset         time.Time // zero until the first API response is seen
+}
+
+// Exhausted reports whether no requests remain until Reset.
+func (rl RateLimit) Exhausted() bool {
+	return !rl.Reset.IsZero() && rl.Remaining <= 0 && time.Now().Before(rl.Reset)
+}
+
+// RateLimitError is returned when a request is refused, or not attempted,
+// because the GitHub API rate limit is exhausted.
+type RateLimitError struct {
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("GitHub API rate limit exceeded, resets at %s", e.Reset.Format(time.RFC3339))
+}
+
+var (
+	rateMu    sync.RWMutex
+	rateLimit = RateLimit{Authenticated: config.Token != "", Remaining: -1}
+)
+
+// GetRateLimit returns the current GitHub API rate-limit state.
+func GetRateLimit() RateLimit {
+	rateMu.RLock()
+	defer rateMu.RUnlock()
+	return rateLimit
+}
+
+// authorize adds the configured GitHub token to req, if any. An
+// authenticated client gets 5,000 API requests per hour instead of 60.
+func authorize(req *http.Request) {
+	if token := GetConfig().Token; token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// checkRateLimit returns a *RateLimitError if the API is known to be
+// exhausted, so callers can skip a request that would only be refused.
+func checkRateLimit() error {
+	rl := GetRateLimit()
+	if rl.Exhausted() {
+		return &RateLimitError{Reset: rl.Reset}
+	}
+	return nil
+}
+
+// recordRateLimit updates the rate-limit state from resp's headers and
+// returns a *RateLimitError if resp is a rate-limit rejection.
+func recordRateLimit(resp *http.Response) error {
+	rateMu.Lock()
+	defer rateMu.Unlock()
+
+	if v, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		rateLimit.Limit = v
+	}
+	if v, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		rateLimit.Remaining = v
+	}
+	if v, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(v, 0)
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+	// Secondary rate limits carry Retry-After instead of exhausting the quota.
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		rateLimit.Remaining = 0
+		rateLimit.Reset = time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	if rateLimit.Remaining == 0 && !rateLimit.Reset.IsZero() {
+		return &RateLimitError{Reset: rateLimit.Reset}
+	}
+	return nil
+}
+
+// nextRefreshDelay returns how long to wait before the next refresh: the
+// regular interval, or until the rate limit resets if that is later.
+func nextRefreshDelay(interval time.Duration) time.Duration {
+	rl := GetRateLimit()
+	if !rl.Exhausted() {
+		return interval
+	}
+	// A little slack so the first request after reset isn't refused again.
+	untilReset := time.Until(rl.Reset) + 5*time.Second
+	if untilReset > interval {
+		return untilReset
+	}
+	return interval
+}
@@ -0,0 +1,110 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// setRateLimit replaces the rate-limit state for the rest of the test.
+func setRateLimit(t *testing.T, rl RateLimit) {
+	rateMu.Lock()
+	previous := rateLimit
+	rateLimit = rl
+	rateMu.Unlock()
+	t.Cleanup(func() {
+		rateMu.Lock()
+		rateLimit = previous
+		rateMu.Unlock()
+	})
+}
+
+func TestRecordRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	resetHeader := strconv.FormatInt(reset.Unix(), 10)
+	tests := []struct {
+		name      string
+		status    int
+		header    map[string]string
+		limited   bool          // a *RateLimitError is returned
+		remaining int           // rate limit state afterwards
+		resetIn   time.Duration // Reset afterwards, from now; 0 for reset
+	}{
+		{"ok", http.StatusOK,
+			map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "59", "X-RateLimit-Reset": resetHeader},
+			false, 59, 0},
+		{"403 with none remaining", http.StatusForbidden,
+			map[string]string{"X-RateLimit-Limit": "60", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": resetHeader},
+			true, 0, 0},
+		{"403 with requests remaining", http.StatusForbidden,
+			map[string]string{"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "4999", "X-RateLimit-Reset": resetHeader},
+			false, 4999, 0},
+		{"429 with Retry-After", http.StatusTooManyRequests,
+			map[string]string{"Retry-After": "30"},
+			true, 0, 30 * time.Second},
+		{"403 secondary limit with Retry-After", http.StatusForbidden,
+			map[string]string{"X-RateLimit-Remaining": "4000", "X-RateLimit-Reset": resetHeader, "Retry-After": "60"},
+			true, 0, time.Minute},
+		{"Retry-After on success", http.StatusOK,
+			map[string]string{"Retry-After": "30"},
+			false, -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRateLimit(t, RateLimit{Remaining: -1})
+			resp := &http.Response{StatusCode: tt.status, Header: make(http.Header)}
+			for k, v := range tt.header {
+				resp.Header.Set(k, v)
+			}
+			err := recordRateLimit(resp)
+			var rle *RateLimitError
+			if limited := errors.As(err, &rle); limited != tt.limited || (err != nil && !limited) {
+				t.Fatalf("recordRateLimit = %v, want a rate-limit error: %v", err, tt.limited)
+			}
+			rl := GetRateLimit()
+			if rl.Remaining != tt.remaining {
+				t.Errorf("Remaining = %d, want %d", rl.Remaining, tt.remaining)
+			}
+			wantReset := reset
+			if tt.resetIn != 0 {
+				wantReset = time.Now().Add(tt.resetIn)
+			} else if tt.header["X-RateLimit-Reset"] == "" {
+				wantReset = time.Time{}
+			}
+			if d := rl.Reset.Sub(wantReset); d < -time.Second || d > time.Second {
+				t.Errorf("Reset = %v, want %v", rl.Reset, wantReset)
+			}
+			if rle != nil && !rle.Reset.Equal(rl.Reset) {
+				t.Errorf("error resets at %v, state at %v", rle.Reset, rl.Reset)
+			}
+			if rl.Exhausted() != tt.limited {
+				t.Errorf("Exhausted = %v, want %v", rl.Exhausted(), tt.limited)
+			}
+		})
+	}
+}
+
+func TestNextRefreshDelay(t *testing.T) {
+	const interval = time.Hour
+	tests := []struct {
+		name string
+		rl   RateLimit
+		want time.Duration
+	}{
+		{"no state yet", RateLimit{Remaining: -1}, interval},
+		{"requests remaining", RateLimit{Remaining: 10, Reset: time.Now().Add(2 * time.Hour)}, interval},
+		{"exhausted past the interval", RateLimit{Remaining: 0, Reset: time.Now().Add(2 * time.Hour)}, 2*time.Hour + 5*time.Second},
+		{"exhausted within the interval", RateLimit{Remaining: 0, Reset: time.Now().Add(10 * time.Minute)}, interval},
+		{"already reset", RateLimit{Remaining: 0, Reset: time.Now().Add(-time.Minute)}, interval},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRateLimit(t, tt.rl)
+			if got := nextRefreshDelay(interval); got > tt.want || got < tt.want-time.Second {
+				t.Errorf("nextRefreshDelay = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
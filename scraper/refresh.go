@@ -0,0 +1,99 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RefreshVersions fetches just the given versions, up to Config.Concurrency
+// at a time, e.g. when a webhook reports their notes changed or an admin
+// asks for one release. Versions not yet discovered, such as the notes of a
+// release published minutes ago, are added to the available versions.
+// Failures are logged, recorded for GetStatus and returned joined.
+func RefreshVersions(ctx context.Context, versions []Version) error {
+	return RefreshVersionsAt(ctx, "", versions)
+}
+
+// RefreshVersionsAt is RefreshVersions reading the notes at ref, a branch,
+// tag or commit SHA; "" means Config.Ref. A webhook passes the SHA of the
+// push, since the raw file CDN may serve a branch's old content for minutes.
+func RefreshVersionsAt(ctx context.Context, ref string, versions []Version) error {
+	var (
+		refreshedMu sync.Mutex
+		refreshed   []Version
+		changed     bool
+	)
+	err := fetchEach(ctx, versions, GetConfig().Concurrency, func(ctx context.Context, version Version) error {
+		_, c, err := fetchAndCacheAt(ctx, version, ref)
+		if err != nil {
+			log.Printf("scraper: refresh: %v", err)
+			recordFetchError(version, err)
+			return err
+		}
+		refreshedMu.Lock()
+		refreshed = append(refreshed, version)
+		changed = changed || c
+		refreshedMu.Unlock()
+		return nil
+	})
+	if changed {
+		rebuildIndex()
+	}
+	if len(refreshed) > 0 {
+		addAvailableVersions(refreshed)
+		log.Printf("scraper: refreshed %d of %d requested versions", len(refreshed), len(versions))
+	}
+	return err
+}
+
+// addAvailableVersions adds any of versions not yet known to the version
+// list, keeping it newest first, and persists the list if it changed.
+func addAvailableVersions(versions []Version) {
+	versionsMu.Lock()
+	list := slices.Clone(availableVersions)
+	for _, v := range versions {
+		if !slices.Contains(list, v) {
+			list = append(list, v)
+		}
+	}
+	changed := len(list) != len(availableVersions)
+	if changed {
+		sortNewestFirst(list)
+		availableVersions = list
+	}
+	versionsMu.Unlock()
+	if changed {
+		persistVersions(list)
+	}
+}
+
+// ChangedVersions returns the versions whose release notes are among paths,
+// the files a push to ref of repo ("owner/name") touched. Pushes to another
+// repository or ref, and any other files, are ignored; so is everything when
+// notes are read from Config.Dir.
+func ChangedVersions(repo, ref string, paths []string) []Version {
+	cfg := GetConfig()
+	if cfg.Dir != "" || !strings.EqualFold(repo, cfg.Owner+"/"+cfg.Repo) {
+		return nil
+	}
+	if ref != cfg.Ref && ref != "refs/heads/"+cfg.Ref && ref != "refs/tags/"+cfg.Ref {
+		return nil
+	}
+	var versions []Version
+	for _, p := range paths {
+		dir, name := path.Split(p)
+		if strings.Trim(dir, "/") != cfg.trimmedPath() || !versionFileRe.MatchString(name) {
+			continue
+		}
+		v, err := ParseVersion(strings.TrimSuffix(name, ".md"))
+		if err == nil && !slices.Contains(versions, v) {
+			versions = append(versions, v)
+		}
+	}
+	sortNewestFirst(versions)
+	return versions
+}
@@ -0,0 +1,111 @@
+package scraper
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// RepoSummary totals the pull requests to one repository, counting both
+// contributors' and documentation PRs.
+type RepoSummary struct {
+	Repo         string `json:"repo"` // "owner/name", as first seen in the notes
+	PRs          int    `json:"prs"`
+	Contributors int    `json:"contributors"`
+	Releases     int    `json:"releases"` // releases with at least one PR to Repo
+}
+
+// Repos returns every repository a PR was made to, most PRs first.
+func (ix *Index) Repos() []RepoSummary {
+	return ix.repos
+}
+
+// Repo returns the summary of repo, given as "owner/name" or "name" as
+// PR.InRepo accepts, or false if no PR was made to it.
+func (ix *Index) Repo(repo string) (RepoSummary, bool) {
+	for _, s := range ix.repos {
+		if (PR{Repo: s.Repo}).InRepo(repo) {
+			return s, true
+		}
+	}
+	return RepoSummary{}, false
+}
+
+// ForRepo returns an index of only the pull requests to repo, given as
+// PR.InRepo accepts, and the contributors who made them; every release is
+// kept, if empty. Issue tracking and localization, which have no PRs, are
+// left out. An empty repo returns ix itself. The result is memoized with
+// ix.
+func (ix *Index) ForRepo(repo string) *Index {
+	if repo == "" {
+		return ix
+	}
+	return ix.derive("repo "+strings.ToLower(repo), func() *Index {
+		releases := make([]Release, 0, len(ix.releases))
+		for _, r := range ix.releases {
+			r.Contributors = contributorsInRepo(r.Contributors, repo)
+			r.Documentation = contributorsInRepo(r.Documentation, repo)
+			r.IssueTracking, r.Localization = nil, nil
+			releases = append(releases, r)
+		}
+		return buildIndex(releases)
+	})
+}
+
+// contributorsInRepo keeps the contributors with PRs to repo, and only
+// those PRs.
+func contributorsInRepo(contributors []Contributor, repo string) []Contributor {
+	var out []Contributor
+	for _, c := range contributors {
+		var prs []PR
+		for _, pr := range c.PRs {
+			if pr.InRepo(repo) {
+				prs = append(prs, pr)
+			}
+		}
+		if len(prs) > 0 {
+			c.PRs = prs
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// repoSummaries totals the PRs in releases per repository.
+func repoSummaries(releases []Release) []RepoSummary {
+	type totals struct {
+		RepoSummary
+		users    map[string]bool
+		releases map[Version]bool
+	}
+	byRepo := make(map[string]*totals)
+	var order []*totals
+	for _, r := range releases {
+		for _, c := range slices.Concat(r.Contributors, r.Documentation) {
+			for _, pr := range c.PRs {
+				if pr.Repo == "" {
+					continue
+				}
+				key := strings.ToLower(pr.Repo)
+				t, ok := byRepo[key]
+				if !ok {
+					t = &totals{RepoSummary: RepoSummary{Repo: pr.Repo}, users: make(map[string]bool), releases: make(map[Version]bool)}
+					byRepo[key] = t
+					order = append(order, t)
+				}
+				t.PRs++
+				t.users[CanonicalUser(c.GitHubUser)] = true
+				t.releases[r.Version] = true
+			}
+		}
+	}
+	repos := make([]RepoSummary, 0, len(order))
+	for _, t := range order {
+		t.Contributors, t.Releases = len(t.users), len(t.releases)
+		repos = append(repos, t.RepoSummary)
+	}
+	slices.SortStableFunc(repos, func(a, b RepoSummary) int {
+		return cmp.Or(b.PRs-a.PRs, strings.Compare(a.Repo, b.Repo))
+	})
+	return repos
+}
@@ -0,0 +1,56 @@
+package scraper
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestForRepo(t *testing.T) {
+	pr := func(repo string) PR { return PR{Repo: repo} }
+	v := func(minor int) Version { return Version{Major: 1, Minor: minor} }
+	ix := NewIndex([]Release{
+		{Version: v(1), Contributors: []Contributor{
+			{GitHubUser: "alice", PRs: []PR{pr("microsoft/vscode"), pr("microsoft/vscode-python")}},
+			{GitHubUser: "bob", PRs: []PR{pr("microsoft/vscode")}},
+		}, IssueTracking: []Contributor{{GitHubUser: "carol"}}},
+		{Version: v(2), Contributors: []Contributor{
+			{GitHubUser: "Bob", PRs: []PR{pr("microsoft/vscode-python"), pr("Microsoft/VSCode-Python")}},
+		}, Documentation: []Contributor{{GitHubUser: "dave", PRs: []PR{pr("microsoft/vscode-docs")}}}},
+	})
+
+	if got := fmt.Sprint(ix.Repos()); got != "[{microsoft/vscode-python 3 2 2} {microsoft/vscode 2 2 1} {microsoft/vscode-docs 1 1 1}]" {
+		t.Errorf("Repos() = %s", got)
+	}
+	if s, ok := ix.Repo("VSCODE"); !ok || s.Repo != "microsoft/vscode" {
+		t.Errorf(`Repo("VSCODE") = %v, %v`, s, ok)
+	}
+	if _, ok := ix.Repo("other/vscode"); ok {
+		t.Error(`Repo("other/vscode") found a repo`)
+	}
+
+	py := ix.ForRepo("vscode-python")
+	if len(py.Releases()) != 2 || len(py.IssueTrackers()) != 0 {
+		t.Errorf("ForRepo kept %d releases and %d issue trackers", len(py.Releases()), len(py.IssueTrackers()))
+	}
+	bob := py.Contributor("bob")
+	if bob == nil || bob.TotalPRs != 2 || bob.FirstRelease != v(2) {
+		t.Fatalf("bob = %+v", bob)
+	}
+	if !py.IsFirstTime("bob", v(2)) || ix.IsFirstTime("bob", v(2)) {
+		t.Error("bob's first PR to vscode-python is not first-time in the repo only")
+	}
+	if py.Contributor("dave") != nil {
+		t.Error("documentation PRs to another repo were kept")
+	}
+	if ix.ForRepo("") != ix {
+		t.Error(`ForRepo("") is not the index itself`)
+	}
+	if ix.ForRepo("VSCode-Python") != py {
+		t.Error("ForRepo rebuilt an index it already built")
+	}
+	from, to := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	if a, b := ix.Between(from, to).ForRepo("vscode"), ix.Between(from.In(time.Local), to).ForRepo("vscode"); a != b {
+		t.Error("Between rebuilt an index it already built")
+	}
+}
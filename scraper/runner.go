@@ -0,0 +1,180 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Runner is the background refresh loop: a refresh, then with
+// Config.Backfill a backfill of any uncached versions, with Config.Enrich a
+// PR enrichment pass and with Config.ResolveUserIDs an identity pass, every
+// Interval plus up to Jitter. When the GitHub rate limit is exhausted the
+// next refresh waits until it resets. Refreshes of single versions, queued
+// with Queue, run alongside the loop. A Runner runs until its context is
+// cancelled or Stop is called.
+type Runner struct {
+	Interval time.Duration // delay between refreshes
+	Jitter   time.Duration // random extra delay, so replicas don't refresh in step
+
+	mu     sync.Mutex
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+	queue  chan versionRefresh
+}
+
+// versionRefresh is a refresh queued with Runner.Queue.
+type versionRefresh struct {
+	ref      string
+	versions []Version
+}
+
+// queueSize is how many refreshes a Runner holds before Queue refuses more.
+const queueSize = 16
+
+// background is the Runner started by StartBackground, if any.
+var background atomic.Pointer[Runner]
+
+// NewRunner returns a Runner that is not yet started.
+func NewRunner(interval, jitter time.Duration) *Runner {
+	return &Runner{Interval: interval, Jitter: jitter, queue: make(chan versionRefresh, queueSize)}
+}
+
+// StartBackground starts a Runner with the configured refresh interval and
+// jitter, which QueueRefresh then uses. It stops when ctx is cancelled or
+// Stop is called.
+func StartBackground(ctx context.Context) *Runner {
+	cfg := GetConfig()
+	r := NewRunner(cfg.RefreshInterval, cfg.RefreshJitter)
+	r.Start(ctx)
+	background.Store(r)
+	return r
+}
+
+// QueueRefresh queues a refresh of versions at ref on the Runner started by
+// StartBackground, as Runner.Queue does. It returns false if there is no
+// such Runner.
+func QueueRefresh(ref string, versions []Version) bool {
+	r := background.Load()
+	return r != nil && r.Queue(ref, versions)
+}
+
+// Start runs the loop in a new goroutine, refreshing immediately. Calling
+// Start on a running Runner does nothing.
+func (r *Runner) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done != nil {
+		return
+	}
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.ctx = ctx
+	r.done = make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.serveQueue(ctx)
+		}()
+		r.run(ctx)
+		wg.Wait()
+	}(r.done)
+}
+
+// Queue asks the Runner to refresh versions at ref, a branch, tag or commit
+// SHA ("" for Config.Ref), as RefreshVersionsAt does. Queued refreshes run
+// one at a time and are cancelled by Stop like the loop. Queue returns false
+// if the Runner is not running or too many refreshes are already queued.
+func (r *Runner) Queue(ref string, versions []Version) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done == nil || r.ctx.Err() != nil {
+		return false
+	}
+	select {
+	case r.queue <- versionRefresh{ref: ref, versions: versions}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop cancels the loop, including any refresh in progress or queued, and
+// waits for it to exit or for ctx to be done, whichever comes first.
+func (r *Runner) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Done is closed once the loop has exited; it is nil before Start.
+func (r *Runner) Done() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.done
+}
+
+func (r *Runner) run(ctx context.Context) {
+	for {
+		r.runOnce(ctx)
+
+		delay := nextRefreshDelay(r.Interval)
+		if delay != r.Interval {
+			log.Printf("scraper: rate limited, next refresh in %s", delay.Round(time.Second))
+		}
+		if r.Jitter > 0 {
+			delay += rand.N(r.Jitter)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Printf("scraper: background refresh stopped")
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// serveQueue runs the refreshes queued with Queue until ctx is done.
+func (r *Runner) serveQueue(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case q := <-r.queue:
+			RefreshVersionsAt(ctx, q.ref, q.versions)
+		}
+	}
+}
+
+// runOnce is one pass of the loop. Each step stops early once ctx is done.
+func (r *Runner) runOnce(ctx context.Context) {
+	Refresh(ctx)
+	cfg := GetConfig()
+	if cfg.Backfill && ctx.Err() == nil {
+		Backfill(ctx, cfg.Concurrency)
+	}
+	if cfg.Enrich && ctx.Err() == nil {
+		EnrichReleases(ctx, cfg.Concurrency)
+	}
+	if cfg.ResolveUserIDs && ctx.Err() == nil {
+		ResolveUserIDs()
+	}
+}
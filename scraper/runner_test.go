@@ -0,0 +1,119 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunnerStop(t *testing.T) {
+	dir := t.TempDir()
+	md := "## Thank you\n\n* [@a (A)](https://github.com/a): x [PR #1](https://github.com/o/r/pull/1)\n"
+	if err := os.WriteFile(filepath.Join(dir, "v1_2.md"), []byte(md), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	previous := GetConfig()
+	cfg := DefaultConfig()
+	cfg.Dir = dir
+	cfg.Backfill = false
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Configure(previous) })
+
+	r := NewRunner(time.Hour, 0)
+	if r.Done() != nil {
+		t.Fatal("Done is set before Start")
+	}
+	r.Start(context.Background())
+	r.Start(context.Background()) // no second loop
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !IsReady() {
+		if time.Now().After(deadline) {
+			t.Fatal("first refresh did not complete")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if _, ok := GetRelease(Version{Major: 1, Minor: 2}); !ok {
+		t.Error("release 1.2 not cached after the first refresh")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	select {
+	case <-r.Done():
+	default:
+		t.Error("Done not closed after Stop")
+	}
+}
+
+func TestRunnerStopsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := NewRunner(time.Hour, time.Minute)
+	r.Start(ctx)
+	select {
+	case <-r.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("runner kept running after its context was cancelled")
+	}
+}
+
+func TestRunnerQueue(t *testing.T) {
+	paths := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.URL.Path, "/abc123/") {
+			http.NotFound(w, r) // the loop's own refresh
+			return
+		}
+		paths <- r.URL.Path
+		w.Write([]byte("## Thank you\n\n* [@a (A)](https://github.com/a): x [PR #1](https://github.com/o/r/pull/1)\n"))
+	}))
+	defer srv.Close()
+	previous := GetConfig()
+	cfg := DefaultConfig()
+	cfg.RawBaseURL, cfg.APIBaseURL = srv.URL, srv.URL
+	cfg.Backfill = false
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Configure(previous) })
+
+	versions := []Version{{Major: 1, Minor: 77}}
+	r := NewRunner(time.Hour, 0)
+	if r.Queue("abc123", versions) {
+		t.Error("Queue accepted a refresh before Start")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.Start(ctx)
+	if !r.Queue("abc123", versions) {
+		t.Fatal("Queue refused a refresh")
+	}
+	select {
+	case p := <-paths:
+		if want := "/" + cfg.Owner + "/" + cfg.Repo + "/abc123/" + cfg.Path + "/v1_77.md"; p != want {
+			t.Errorf("fetched %s, want %s", p, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("queued refresh did not run")
+	}
+
+	cancel()
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer stopCancel()
+	if err := r.Stop(stopCtx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if r.Queue("abc123", versions) {
+		t.Error("Queue accepted a refresh after Stop")
+	}
+}
@@ -1,110 +1,187 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"regexp"
-	"sort"
-	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type PR struct {
-	Title  string
-	URL    string
-	Repo   string
-	Number string
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Repo   string `json:"repo"`
+	Number string `json:"number"`
+
+	// Set by the optional GitHub API enrichment pass (see EnrichReleases);
+	// zero values mean the PR has not been enriched.
+	Enriched     bool      `json:"enriched"`
+	MergedAt     time.Time `json:"merged_at,omitzero"`
+	Labels       []string  `json:"labels,omitempty"`
+	Additions    int       `json:"additions,omitempty"`
+	Deletions    int       `json:"deletions,omitempty"`
+	ChangedFiles int       `json:"changed_files,omitempty"`
+	LinkedIssues []string  `json:"linked_issues,omitempty"` // "owner/repo#number" of issues the PR closes
+	AuthorID     int64     `json:"author_id,omitempty"`     // GitHub user ID of the PR's author
+	AuthorLogin  string    `json:"author_login,omitempty"`  // the author's login when the PR was enriched
+}
+
+// InRepo reports whether pr was made to repo, given as "owner/name" or just
+// "name"; case is ignored.
+func (pr PR) InRepo(repo string) bool {
+	if strings.EqualFold(pr.Repo, repo) {
+		return true
+	}
+	_, name, _ := strings.Cut(pr.Repo, "/")
+	return !strings.Contains(repo, "/") && strings.EqualFold(name, repo)
 }
 
 type Contributor struct {
-	Name       string
-	GitHubUser string
-	AvatarURL  string
-	PRs        []PR
+	Name       string `json:"name"`
+	GitHubUser string `json:"github_user"`
+	AvatarURL  string `json:"avatar_url"`
+	PRs        []PR   `json:"prs,omitempty"`
 }
 
 // ContributorSearchResult represents aggregated contributor data across releases.
 type ContributorSearchResult struct {
-	GitHubUser   string
-	Name         string
-	AvatarURL    string
-	TotalPRs     int
-	ReleaseCount int
+	GitHubUser   string `json:"github_user"`
+	Name         string `json:"name"`
+	AvatarURL    string `json:"avatar_url"`
+	TotalPRs     int    `json:"total_prs"`
+	ReleaseCount int    `json:"release_count"`
+}
+
+// Translator is a localization contributor. The release notes usually list
+// translators by name and language only, so GitHubUser is often empty.
+type Translator struct {
+	Name       string `json:"name"`
+	GitHubUser string `json:"github_user,omitempty"`
+	Language   string `json:"language"`
 }
 
 type Release struct {
-	Version      string
-	DisplayName  string
-	Contributors []Contributor
+	Version      Version       `json:"version"`
+	DisplayName  string        `json:"display_name"`      // Version.String(), for templates
+	Recovery     Version       `json:"recovery,omitzero"` // latest recovery release, e.g. 1.109.2; zero if none
+	Contributors []Contributor `json:"contributors"`      // pull request contributors
+
+	// Date is when the release shipped, from the notes' front matter. When
+	// DateMonthOnly is set it is only the first of the month the release is
+	// named after. The zero time means the notes give no date.
+	Date          time.Time `json:"date,omitzero"`
+	DateMonthOnly bool      `json:"date_month_only,omitempty"`
+
+	// Contributions thanked outside the pull request list.
+	IssueTracking []Contributor `json:"issue_tracking"` // issue reporters and triagers; no PRs
+	Documentation []Contributor `json:"documentation"`  // pull requests to the documentation
+	Localization  []Translator  `json:"localization"`
+
+	Warnings []ParseWarning `json:"warnings,omitempty"` // lines in the notes the parser could not interpret
+}
+
+// DateString formats Date for display: "Feb 4, 2026", "January 2026" when
+// only the month is known, or "" when there is no date.
+func (r Release) DateString() string {
+	switch {
+	case r.Date.IsZero():
+		return ""
+	case r.DateMonthOnly:
+		return r.Date.Format("January 2006")
+	default:
+		return r.Date.Format("Jan 2, 2006")
+	}
 }
 
 // ContributorHistory aggregates a contributor's activity across all releases.
 type ContributorHistory struct {
-	GitHubUser    string
-	Name          string
-	AvatarURL     string
-	TotalPRs      int
-	ReleaseCount  int
-	FirstRelease  string            // version of first contribution
-	LatestRelease string            // version of most recent contribution
-	PRsByRelease  map[string][]PR   // version -> PRs
+	GitHubUser    string           `json:"github_user"`
+	Name          string           `json:"name"`
+	AvatarURL     string           `json:"avatar_url"`
+	TotalPRs      int              `json:"total_prs"`
+	ReleaseCount  int              `json:"release_count"`
+	FirstRelease  Version          `json:"first_release,omitzero"`  // version of first contribution
+	LatestRelease Version          `json:"latest_release,omitzero"` // version of most recent contribution
+	PRsByRelease  map[Version][]PR `json:"prs_by_release"`          // version -> PRs
+
+	// Non-PR contributions. TotalPRs, ReleaseCount and the release fields
+	// above count pull requests only.
+	IssueTrackingReleases []Version        `json:"issue_tracking_releases"` // versions thanked for issue tracking, newest first
+	TotalDocsPRs          int              `json:"total_docs_prs"`
+	DocsPRsByRelease      map[Version][]PR `json:"docs_prs_by_release"` // version -> documentation PRs
 }
 
-// VersionInfo holds a version identifier and its display name.
-type VersionInfo struct {
-	ID      string // e.g. "v1_109"
-	Display string // e.g. "1.109"
+// TranslatorHistory aggregates a translator's releases. Translators are
+// keyed by name, since the notes rarely give a GitHub login.
+type TranslatorHistory struct {
+	Name          string   `json:"name"`
+	GitHubUser    string   `json:"github_user,omitempty"`
+	Languages     []string `json:"languages"`
+	ReleaseCount  int      `json:"release_count"`
+	LatestRelease Version  `json:"latest_release"`
 }
 
 var (
 	mu     sync.RWMutex
-	cached = make(map[string]Release)
+	cached = make(map[Version]Release)
 
 	versionsMu        sync.RWMutex
-	availableVersions []VersionInfo
+	availableVersions []Version
 )
 
 // fallbackVersions is used when the GitHub API is unavailable.
-var fallbackVersions = []string{
-	"v1_109", "v1_108", "v1_107", "v1_106", "v1_105",
+var fallbackVersions = []Version{
+	{1, 109, 0}, {1, 108, 0}, {1, 107, 0}, {1, 106, 0}, {1, 105, 0},
 }
 
 // prefetchCount is the number of recent versions to pre-fetch on startup.
 const prefetchCount = 5
 
-var client = &http.Client{Timeout: 30 * time.Second}
+// client has no overall timeout; each fetch runs under a context deadline
+// of Config.FetchTimeout instead.
+var client = &http.Client{}
 
 // GetAvailableVersions returns all known release versions (newest first).
-func GetAvailableVersions() []VersionInfo {
+func GetAvailableVersions() []Version {
 	versionsMu.RLock()
 	defer versionsMu.RUnlock()
 	return availableVersions
 }
 
 // GetRelease returns a single release, fetching on-demand if not cached.
-func GetRelease(version string) (Release, bool) {
+// A recovery version returns the release whose notes cover it.
+// Releases fetched here are also written to the configured Store. Concurrent
+// requests for the same uncached version share one fetch, and versions that
+// recently failed to fetch are not retried until the negative cache expires.
+func GetRelease(version Version) (Release, bool) {
+	version = version.Base()
 	mu.RLock()
 	r, ok := cached[version]
 	mu.RUnlock()
 	if ok {
 		return r, true
 	}
+	if version.IsZero() {
+		return Release{}, false
+	}
 
 	// Fetch on demand
-	rel, err := fetchRelease(version)
+	rel, err := fetchOnce(version)
+	if errors.Is(err, errRecentlyFailed) {
+		return Release{}, false
+	}
 	if err != nil {
-		log.Printf("scraper: failed to fetch %s: %v", version, err)
+		log.Printf("scraper: %v", err)
 		return Release{}, false
 	}
-
-	mu.Lock()
-	cached[version] = rel
-	mu.Unlock()
 	return rel, true
 }
 
@@ -119,7 +196,7 @@ func GetReleases() []Release {
 
 	var results []Release
 	for _, v := range versions {
-		if r, ok := cached[v.ID]; ok && len(r.Contributors) > 0 {
+		if r, ok := cached[v]; ok && len(r.Contributors) > 0 {
 			results = append(results, r)
 		}
 	}
@@ -127,136 +204,57 @@ func GetReleases() []Release {
 }
 
 // SearchContributors searches all cached releases for contributors matching the query.
-// The search is case-insensitive and matches partial GitHub usernames.
+// The search is case-insensitive and matches partial GitHub usernames; results
+// come from the contributor index, so renamed accounts appear once.
 func SearchContributors(query string) []ContributorSearchResult {
-	mu.RLock()
-	defer mu.RUnlock()
-
 	query = strings.ToLower(query)
 	if query == "" {
 		return nil
 	}
 
-	// Aggregate data by GitHub username (lowercase for deduplication)
-	type aggregated struct {
-		GitHubUser   string
-		Name         string
-		AvatarURL    string
-		TotalPRs     int
-		ReleaseCount int
-	}
-	byUser := make(map[string]*aggregated)
-
-	for _, release := range cached {
-		for _, contrib := range release.Contributors {
-			userLower := strings.ToLower(contrib.GitHubUser)
-			if !strings.Contains(userLower, query) {
-				continue
-			}
-
-			if agg, exists := byUser[userLower]; exists {
-				agg.TotalPRs += len(contrib.PRs)
-				agg.ReleaseCount++
-			} else {
-				byUser[userLower] = &aggregated{
-					GitHubUser:   contrib.GitHubUser,
-					Name:         contrib.Name,
-					AvatarURL:    contrib.AvatarURL,
-					TotalPRs:     len(contrib.PRs),
-					ReleaseCount: 1,
-				}
-			}
+	// Index entries are already ranked by TotalPRs descending
+	var results []ContributorSearchResult
+	for _, h := range GetIndex().Contributors() {
+		if !strings.Contains(strings.ToLower(h.GitHubUser), query) {
+			continue
 		}
-	}
-
-	// Convert to result slice
-	results := make([]ContributorSearchResult, 0, len(byUser))
-	for _, agg := range byUser {
 		results = append(results, ContributorSearchResult{
-			GitHubUser:   agg.GitHubUser,
-			Name:         agg.Name,
-			AvatarURL:    agg.AvatarURL,
-			TotalPRs:     agg.TotalPRs,
-			ReleaseCount: agg.ReleaseCount,
+			GitHubUser:   h.GitHubUser,
+			Name:         h.Name,
+			AvatarURL:    h.AvatarURL,
+			TotalPRs:     h.TotalPRs,
+			ReleaseCount: h.ReleaseCount,
 		})
 	}
-
-	// Sort by TotalPRs descending for consistent ordering
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].TotalPRs > results[j].TotalPRs
-	})
-
 	return results
 }
 
-// GetContributorHistory returns aggregated contribution history for a user.
-// Returns nil if the user is not found in any cached release.
+// GetContributorHistory returns aggregated contribution history for a user,
+// including activity under any aliased login. Returns nil if the user is not
+// found in any cached release. The result is shared and must not be modified.
 func GetContributorHistory(username string) *ContributorHistory {
-	mu.RLock()
-	defer mu.RUnlock()
-
-	history := &ContributorHistory{
-		PRsByRelease: make(map[string][]PR),
-	}
-
-	usernameLower := strings.ToLower(username)
-	var firstVersion, latestVersion string
-	var firstVersionNum, latestVersionNum int
-
-	for version, release := range cached {
-		for _, contrib := range release.Contributors {
-			if strings.ToLower(contrib.GitHubUser) == usernameLower {
-				// Set user info from first match
-				if history.GitHubUser == "" {
-					history.GitHubUser = contrib.GitHubUser
-					history.Name = contrib.Name
-					history.AvatarURL = contrib.AvatarURL
-				}
-
-				// Add PRs for this release
-				if len(contrib.PRs) > 0 {
-					history.PRsByRelease[version] = append(history.PRsByRelease[version], contrib.PRs...)
-					history.TotalPRs += len(contrib.PRs)
-				}
-				history.ReleaseCount++
-
-				// Track first and latest release
-				vNum := versionNumber(version)
-				if firstVersion == "" || vNum < firstVersionNum {
-					firstVersion = version
-					firstVersionNum = vNum
-				}
-				if latestVersion == "" || vNum > latestVersionNum {
-					latestVersion = version
-					latestVersionNum = vNum
-				}
-				break // Found contributor in this release, move to next
-			}
-		}
-	}
-
-	// Return nil if user not found
-	if history.GitHubUser == "" {
-		return nil
-	}
-
-	history.FirstRelease = firstVersion
-	history.LatestRelease = latestVersion
-	return history
+	return GetIndex().Contributor(username)
 }
 
-// Refresh discovers available versions and pre-fetches recent ones.
-func Refresh() []Release {
+// Refresh discovers available versions and pre-fetches recent ones, up to
+// Config.Concurrency at a time. Discovery and per-version fetch failures are
+// logged and also returned joined; the releases are returned either way.
+// The outcome is recorded for GetStatus.
+func Refresh(ctx context.Context) ([]Release, error) {
+	var errs []error
+
 	// Discover all available versions
-	versions, err := discoverVersions()
+	versions, err := discoverVersions(ctx)
 	if err != nil {
 		log.Printf("scraper: failed to discover versions: %v", err)
+		errs = append(errs, fmt.Errorf("discover versions: %w", err))
 		// Use fallback if discovery fails and we have nothing cached
 		versionsMu.RLock()
 		hasVersions := len(availableVersions) > 0
 		versionsMu.RUnlock()
 		if !hasVersions {
-			versions = toVersionInfos(fallbackVersions)
+			versions = fallbackVersions
+			setUsingFallback(true)
 		} else {
 			versionsMu.RLock()
 			versions = availableVersions
@@ -264,49 +262,73 @@ func Refresh() []Release {
 		}
 	}
 
+	discovered := err == nil && len(versions) > 0
 	versionsMu.Lock()
 	availableVersions = versions
 	versionsMu.Unlock()
+	if err == nil {
+		persistVersions(versions)
+		setUsingFallback(false)
+	}
 
 	// Pre-fetch the most recent versions
 	limit := prefetchCount
 	if limit > len(versions) {
 		limit = len(versions)
 	}
-	for _, v := range versions[:limit] {
-		r, err := fetchRelease(v.ID)
+	var changed atomic.Bool
+	err = fetchEach(ctx, versions[:limit], GetConfig().Concurrency, func(ctx context.Context, version Version) error {
+		_, c, err := fetchAndCache(ctx, version)
 		if err != nil {
-			log.Printf("scraper: failed to fetch %s: %v", v.ID, err)
-			continue
+			log.Printf("scraper: %v", err)
+			recordFetchError(version, err)
 		}
-		mu.Lock()
-		cached[v.ID] = r
-		mu.Unlock()
+		if c {
+			changed.Store(true)
+		}
+		return err
+	})
+	if changed.Load() {
+		rebuildIndex()
+	}
+	if err != nil {
+		errs = append(errs, err)
 	}
 
-	log.Printf("scraper: discovered %d versions, pre-fetched %d", len(versions), limit)
-	return GetReleases()
+	stats := GetFetchStats()
+	log.Printf("scraper: discovered %d versions, pre-fetched %d (%d not modified, %d downloaded so far)",
+		len(versions), limit, stats.Hits, stats.Downloads)
+	err = errors.Join(errs...)
+	releases := GetReleases()
+	recordRefresh(err, discovered || len(releases) > 0)
+	return releases, err
 }
 
-// StartBackground begins periodic scraping in the background.
-func StartBackground() {
-	go func() {
-		Refresh()
-		ticker := time.NewTicker(1 * time.Hour)
-		for range ticker.C {
-			Refresh()
-		}
-	}()
-}
+// discoverVersions lists release note files from the configured GitHub repo,
+// or from the local directory when Config.Dir is set. The HTTP listing is
+// requested conditionally; on 304 the previous result is reused.
+func discoverVersions(ctx context.Context) ([]Version, error) {
+	if dir := GetConfig().Dir; dir != "" {
+		return discoverLocalVersions(dir)
+	}
+	if err := checkRateLimit(); err != nil {
+		return nil, err
+	}
 
-// discoverVersions lists release note files from the vscode-docs GitHub repo.
-func discoverVersions() ([]VersionInfo, error) {
-	url := "https://api.github.com/repos/microsoft/vscode-docs/contents/release-notes"
-	req, err := http.NewRequest("GET", url, nil)
+	url := GetConfig().contentsURL()
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	authorize(req)
+
+	validatorsMu.Lock()
+	previous := lastListing
+	validatorsMu.Unlock()
+	if previous != nil {
+		addValidators(req)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -314,224 +336,115 @@ func discoverVersions() ([]VersionInfo, error) {
 	}
 	defer resp.Body.Close()
 
+	if err := recordRateLimit(resp); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotModified && previous != nil {
+		fetchHits.Add(1)
+		return previous, nil
+	}
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("GitHub API HTTP %d", resp.StatusCode)
 	}
+	fetchDownloads.Add(1)
 
 	var entries []struct {
 		Name string `json:"name"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		forgetValidators(url)
 		return nil, err
 	}
 
-	versionFileRe := regexp.MustCompile(`^(v\d+_\d+)\.md$`)
-	var versions []VersionInfo
+	names := make([]string, 0, len(entries))
 	for _, e := range entries {
-		m := versionFileRe.FindStringSubmatch(e.Name)
-		if m == nil {
-			continue
-		}
-		id := m[1]
-		display := strings.TrimPrefix(id, "v")
-		display = strings.Replace(display, "_", ".", 1)
-		versions = append(versions, VersionInfo{ID: id, Display: display})
+		names = append(names, e.Name)
 	}
+	versions := versionsFromFileNames(names)
 
-	// Sort by version number descending (newest first)
-	sort.Slice(versions, func(i, j int) bool {
-		return versionNumber(versions[i].ID) > versionNumber(versions[j].ID)
-	})
+	rememberValidators(url, resp)
+	validatorsMu.Lock()
+	lastListing = versions
+	validatorsMu.Unlock()
 
 	return versions, nil
 }
 
-func versionNumber(id string) int {
-	// "v1_109" -> 1109, "v1_99" -> 199
-	s := strings.TrimPrefix(id, "v")
-	parts := strings.SplitN(s, "_", 2)
-	if len(parts) != 2 {
-		return 0
+// versionFileRe matches release-notes file names such as v1_109.md.
+var versionFileRe = regexp.MustCompile(`^(v\d+_\d+)\.md$`)
+
+// errRecentlyFailed is returned for versions held in the negative cache.
+var errRecentlyFailed = errors.New("fetch failed recently, not retrying yet")
+
+// versionsFromFileNames picks the vX_YY.md release-notes files out of a
+// directory listing and returns them newest first.
+func versionsFromFileNames(names []string) []Version {
+	var versions []Version
+	for _, name := range names {
+		m := versionFileRe.FindStringSubmatch(name)
+		if m == nil {
+			continue
+		}
+		if v, err := ParseVersion(m[1]); err == nil {
+			versions = append(versions, v)
+		}
 	}
-	major, _ := strconv.Atoi(parts[0])
-	minor, _ := strconv.Atoi(parts[1])
-	return major*10000 + minor
+	sortNewestFirst(versions)
+	return versions
 }
 
-func toVersionInfos(ids []string) []VersionInfo {
-	var out []VersionInfo
-	for _, id := range ids {
-		display := strings.TrimPrefix(id, "v")
-		display = strings.Replace(display, "_", ".", 1)
-		out = append(out, VersionInfo{ID: id, Display: display})
+// fetchRelease downloads and parses the release notes for version at ref
+// ("" for Config.Ref), reading from Config.Dir instead when it is set. If the
+// release is already cached the request is conditional, and a 304 returns
+// the cached copy with changed=false. Fetches at a given ref, such as the
+// commit of a push, are never conditional: the content at a commit SHA
+// cannot change, and each SHA is a new URL.
+func fetchRelease(ctx context.Context, version Version, ref string) (rel Release, changed bool, err error) {
+	if dir := GetConfig().Dir; dir != "" {
+		return fetchLocalRelease(ctx, dir, version)
+	}
+	url := GetConfig().rawURL(version, ref)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return Release{}, false, err
 	}
-	return out
-}
 
-func fetchRelease(version string) (Release, error) {
-	url := fmt.Sprintf("https://raw.githubusercontent.com/microsoft/vscode-docs/main/release-notes/%s.md", version)
+	mu.RLock()
+	previous, hasPrevious := cached[version]
+	mu.RUnlock()
+	hasPrevious = hasPrevious && ref == ""
+	if hasPrevious {
+		addValidators(req)
+	}
 
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
-		return Release{}, err
+		return Release{}, false, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && hasPrevious {
+		fetchHits.Add(1)
+		return previous, false, nil
+	}
 	if resp.StatusCode != 200 {
-		return Release{}, fmt.Errorf("HTTP %d for %s", resp.StatusCode, url)
+		return Release{}, false, fmt.Errorf("HTTP %d for %s", resp.StatusCode, url)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return Release{}, err
+		return Release{}, false, err
 	}
-
-	return parseMarkdown(version, string(body)), nil
-}
-
-// Regex patterns for markdown parsing.
-var (
-	// Matches: * [@username (Display Name)](https://github.com/username)...
-	contribLineRe = regexp.MustCompile(`^\* \[@([^\]]+)\]\(https://github\.com/([^)]+)\)(.*)$`)
-	// Matches indented sub-items
-	subItemRe = regexp.MustCompile(`^\s+\* (.+)$`)
-	// Matches PR links: [PR #123](https://github.com/org/repo/pull/123)
-	prLinkRe = regexp.MustCompile(`\[PR #(\d+)\]\((https://github\.com/([^)]+)/pull/\d+)\)`)
-	// Matches repo section headers: Contributions to `repo`:
-	repoSectionRe = regexp.MustCompile("^Contributions to `([^`]+)`:?$")
-)
-
-func parseMarkdown(version, md string) Release {
-	display := strings.TrimPrefix(version, "v")
-	display = strings.Replace(display, "_", ".", 1)
-
-	release := Release{
-		Version:     version,
-		DisplayName: display,
+	fetchDownloads.Add(1)
+	if ref == "" {
+		rememberValidators(url, resp)
 	}
 
-	lines := strings.Split(md, "\n")
-	inPRSection := false
-	var currentContrib *Contributor
-
-	for _, line := range lines {
-		line = strings.TrimRight(line, "\r")
-
-		if strings.HasPrefix(line, "### Pull Requests") {
-			inPRSection = true
-			continue
-		}
-		if !inPRSection {
-			continue
-		}
-
-		// Stop at next major heading
-		if strings.HasPrefix(line, "## ") {
-			break
-		}
-
-		// Repo section header (informational, we get repo from PR URL)
-		if repoSectionRe.MatchString(line) {
-			currentContrib = nil
-			continue
-		}
-
-		// Contributor line
-		if m := contribLineRe.FindStringSubmatch(line); m != nil {
-			displayText := m[1]
-			githubUser := m[2]
-			rest := m[3]
-
-			name := githubUser
-			if idx := strings.Index(displayText, " ("); idx != -1 {
-				name = strings.TrimSuffix(displayText[idx+2:], ")")
-			}
-
-			c := Contributor{
-				Name:       name,
-				GitHubUser: githubUser,
-				AvatarURL:  fmt.Sprintf("https://github.com/%s.png?size=80", githubUser),
-			}
-
-			// Extract PRs from rest of line
-			if prMatches := prLinkRe.FindAllStringSubmatch(rest, -1); len(prMatches) > 0 {
-				for _, pm := range prMatches {
-					desc := extractDescription(rest, pm[0])
-					c.PRs = append(c.PRs, PR{
-						Title:  desc,
-						URL:    pm[2],
-						Repo:   pm[3],
-						Number: pm[1],
-					})
-				}
-			}
-
-			release.Contributors = append(release.Contributors, c)
-			currentContrib = &release.Contributors[len(release.Contributors)-1]
-			continue
-		}
-
-		// Sub-item (belongs to current contributor)
-		if currentContrib != nil {
-			if m := subItemRe.FindStringSubmatch(line); m != nil {
-				content := m[1]
-				if prMatches := prLinkRe.FindAllStringSubmatch(content, -1); len(prMatches) > 0 {
-					for _, pm := range prMatches {
-						desc := extractDescription(content, pm[0])
-						currentContrib.PRs = append(currentContrib.PRs, PR{
-							Title:  desc,
-							URL:    pm[2],
-							Repo:   pm[3],
-							Number: pm[1],
-						})
-					}
-				}
-			}
-		}
-	}
-
-	return release
-}
-
-func extractDescription(text, prLink string) string {
-	idx := strings.Index(text, prLink)
-	if idx <= 0 {
-		return ""
-	}
-	desc := strings.TrimSpace(text[:idx])
-	desc = strings.TrimPrefix(desc, ": ")
-	desc = strings.TrimPrefix(desc, ":")
-	desc = strings.TrimSpace(desc)
-	return desc
+	return parseMarkdown(version, string(body)), true, nil
 }
 
 // IsFirstTimeContributor returns true if this is the first release where the user contributed.
 // It checks all cached releases with versions BEFORE the given version.
-func IsFirstTimeContributor(username string, version string) bool {
-	versionsMu.RLock()
-	versions := availableVersions
-	versionsMu.RUnlock()
-
-	targetNum := versionNumber(version)
-
-	mu.RLock()
-	defer mu.RUnlock()
-
-	for _, v := range versions {
-		// Only check releases BEFORE the given version
-		if versionNumber(v.ID) >= targetNum {
-			continue
-		}
-		rel, ok := cached[v.ID]
-		if !ok {
-			continue
-		}
-		for _, c := range rel.Contributors {
-			if strings.EqualFold(c.GitHubUser, username) {
-				// User contributed in an earlier release
-				return false
-			}
-		}
-	}
-	return true
+func IsFirstTimeContributor(username string, version Version) bool {
+	return GetIndex().IsFirstTime(username, version)
 }
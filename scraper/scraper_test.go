@@ -0,0 +1,125 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+	}{
+		{"1.109", Version{1, 109, 0}},
+		{"v1.109", Version{1, 109, 0}},
+		{"v1_109", Version{1, 109, 0}},
+		{"1_5", Version{1, 5, 0}},
+		{"1.109.1", Version{1, 109, 1}},
+		{"v1_109_2", Version{1, 109, 2}},
+		{"v2.0", Version{2, 0, 0}},
+	}
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{"", "v1", "1", "1.2.3.4", "1._2", "1.-2", "1. 2", "v0.0", "1.x", "1.2_3", "1234567890.1"} {
+		if v, err := ParseVersion(in); err == nil {
+			t.Errorf("ParseVersion(%q) = %v, want error", in, v)
+		}
+	}
+}
+
+func TestVersionFormats(t *testing.T) {
+	tests := []struct {
+		v            Version
+		str, tag, id string
+		recovery     bool
+	}{
+		{Version{1, 109, 0}, "1.109", "v1.109", "v1_109", false},
+		{Version{1, 109, 1}, "1.109.1", "v1.109.1", "v1_109_1", true},
+		{Version{}, "", "", "", false},
+	}
+	for _, tt := range tests {
+		if got := tt.v.String(); got != tt.str {
+			t.Errorf("%#v.String() = %q, want %q", tt.v, got, tt.str)
+		}
+		if got := tt.v.Tag(); got != tt.tag {
+			t.Errorf("%#v.Tag() = %q, want %q", tt.v, got, tt.tag)
+		}
+		if got := tt.v.ID(); got != tt.id {
+			t.Errorf("%#v.ID() = %q, want %q", tt.v, got, tt.id)
+		}
+		if got := tt.v.IsRecovery(); got != tt.recovery {
+			t.Errorf("%#v.IsRecovery() = %v, want %v", tt.v, got, tt.recovery)
+		}
+	}
+}
+
+func TestVersionJSON(t *testing.T) {
+	in := map[string]Version{"a": {1, 109, 0}, "b": {1, 109, 2}, "c": {}}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"a":"1.109","b":"1.109.2","c":""}`; string(b) != want {
+		t.Errorf("json = %s, want %s", b, want)
+	}
+	var out map[string]Version
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(out) != fmt.Sprint(in) {
+		t.Errorf("round trip = %v, want %v", out, in)
+	}
+}
+
+func TestVersionsFromFileNames(t *testing.T) {
+	got := versionsFromFileNames([]string{"v1_99.md", "README.md", "v1_109.md", "v1_100.md", "images"})
+	want := []Version{{1, 109, 0}, {1, 100, 0}, {1, 99, 0}}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("versionsFromFileNames = %v, want %v", got, want)
+	}
+}
+
+// FuzzParseVersion checks every accepted version survives a round trip
+// through each of its forms.
+func FuzzParseVersion(f *testing.F) {
+	f.Add("v1_109")
+	f.Add("1.109.1")
+	f.Add("v1.9")
+	f.Fuzz(func(t *testing.T, s string) {
+		v, err := ParseVersion(s)
+		if err != nil {
+			return
+		}
+		if v.IsZero() {
+			t.Fatalf("ParseVersion(%q) accepted the zero version", s)
+		}
+		for _, form := range []string{v.String(), v.Tag(), v.ID()} {
+			if w, err := ParseVersion(form); err != nil || w != v {
+				t.Errorf("ParseVersion(%q) = %v, %v, want %v (from %q)", form, w, err, v, s)
+			}
+		}
+		if v.Base().IsRecovery() || v.Base().Less(v) != v.IsRecovery() {
+			t.Errorf("%v.Base() = %v", v, v.Base())
+		}
+	})
+}
+
+// FuzzVersionCompare checks versions order like their numbers.
+func FuzzVersionCompare(f *testing.F) {
+	f.Add(uint16(1), uint16(99), uint16(0), uint16(1), uint16(109), uint16(0))
+	f.Add(uint16(1), uint16(109), uint16(1), uint16(1), uint16(109), uint16(0))
+	f.Fuzz(func(t *testing.T, ma1, mi1, p1, ma2, mi2, p2 uint16) {
+		a := Version{int(ma1), int(mi1), int(p1)}
+		b := Version{int(ma2), int(mi2), int(p2)}
+		n1 := uint64(ma1)<<32 | uint64(mi1)<<16 | uint64(p1)
+		n2 := uint64(ma2)<<32 | uint64(mi2)<<16 | uint64(p2)
+		if a.Less(b) != (n1 < n2) || (a.Compare(b) == 0) != (n1 == n2) || a.Compare(b) != -b.Compare(a) {
+			t.Errorf("%v vs %v: Compare = %d, Less = %v", a, b, a.Compare(b), a.Less(b))
+		}
+	})
+}
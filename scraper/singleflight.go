@@ -0,0 +1,98 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// negativeCacheTTL is how long a failed on-demand fetch is remembered, so
+// repeated requests for a missing or broken version don't each hit GitHub.
+const negativeCacheTTL = 5 * time.Minute
+
+// maxFailures caps the negative cache. Versions come from request paths, so
+// a crawler probing many bogus ones must not grow it without bound; past
+// the cap the entries closest to expiring are forgotten first.
+const maxFailures = 1024
+
+// flight is an in-progress on-demand fetch that concurrent callers share.
+type flight struct {
+	done chan struct{}
+	rel  Release
+	err  error
+}
+
+var (
+	flightsMu sync.Mutex
+	flights   = make(map[Version]*flight)
+	// failedUntil maps a version to the time its last failure expires.
+	failedUntil = make(map[Version]time.Time)
+)
+
+// fetchOnce fetches version on behalf of GetRelease. Concurrent calls for
+// the same version share a single fetch, and a failure is returned from
+// the negative cache for negativeCacheTTL without fetching again.
+func fetchOnce(version Version) (Release, error) {
+	flightsMu.Lock()
+	if until, ok := failedUntil[version]; ok {
+		if time.Now().Before(until) {
+			flightsMu.Unlock()
+			return Release{}, &FetchError{Version: version, Err: errRecentlyFailed}
+		}
+		delete(failedUntil, version)
+	}
+	if f, ok := flights[version]; ok {
+		flightsMu.Unlock()
+		<-f.done
+		return f.rel, f.err
+	}
+	f := &flight{done: make(chan struct{})}
+	flights[version] = f
+	flightsMu.Unlock()
+
+	var changed bool
+	f.rel, changed, f.err = fetchAndCache(context.Background(), version)
+	if changed {
+		rebuildIndex()
+	}
+
+	flightsMu.Lock()
+	delete(flights, version)
+	if f.err != nil {
+		sweepFailures()
+		failedUntil[version] = time.Now().Add(negativeCacheTTL)
+	}
+	flightsMu.Unlock()
+	close(f.done)
+	return f.rel, f.err
+}
+
+// sweepFailures drops expired negative-cache entries, and then the ones
+// closest to expiring until there is room for one more under maxFailures.
+// Callers hold flightsMu.
+func sweepFailures() {
+	now := time.Now()
+	for v, until := range failedUntil {
+		if now.After(until) {
+			delete(failedUntil, v)
+		}
+	}
+	for len(failedUntil) >= maxFailures {
+		var oldest Version
+		var oldestUntil time.Time
+		for v, until := range failedUntil {
+			if oldestUntil.IsZero() || until.Before(oldestUntil) {
+				oldest, oldestUntil = v, until
+			}
+		}
+		delete(failedUntil, oldest)
+	}
+}
+
+// forgetFailure clears any negative-cache entry for version, e.g. after a
+// background refresh fetched it successfully.
+func forgetFailure(version Version) {
+	flightsMu.Lock()
+	delete(failedUntil, version)
+	flightsMu.Unlock()
+}
@@ -0,0 +1,97 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetReleaseFetchesOnce(t *testing.T) {
+	var requests sync.Map // path -> *atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := requests.LoadOrStore(r.URL.Path, new(atomic.Int32))
+		n.(*atomic.Int32).Add(1)
+		<-release
+		if strings.HasSuffix(r.URL.Path, "/v7_2.md") {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("## Thank you\n\n* [@a (A)](https://github.com/a): x [PR #1](https://github.com/o/r/pull/1)\n"))
+	}))
+	defer srv.Close()
+	previous := GetConfig()
+	cfg := DefaultConfig()
+	cfg.RawBaseURL, cfg.APIBaseURL = srv.URL, srv.URL
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	ok, failing := Version{Major: 7, Minor: 1}, Version{Major: 7, Minor: 2}
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(cached, ok)
+		mu.Unlock()
+		forgetFailure(failing)
+		Configure(previous)
+		rebuildIndex()
+	})
+
+	// Callers that arrive while the fetch is in flight wait for it; later
+	// ones find the release cached or the failure remembered.
+	var wg sync.WaitGroup
+	found := make([]bool, 20)
+	for i := range found {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := ok
+			if i%2 == 1 {
+				v = failing
+			}
+			_, found[i] = GetRelease(v)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i, f := range found {
+		if f != (i%2 == 0) {
+			t.Errorf("call %d found a release: %v", i, f)
+		}
+	}
+	if _, found := GetRelease(failing); found {
+		t.Error("GetRelease found the failing version on a later call")
+	}
+	requests.Range(func(path, n interface{}) bool {
+		if n := n.(*atomic.Int32).Load(); n != 1 {
+			t.Errorf("%s requested %d times, want 1", path, n)
+		}
+		return true
+	})
+}
+
+func TestSweepFailures(t *testing.T) {
+	flightsMu.Lock()
+	previous := failedUntil
+	failedUntil = make(map[Version]time.Time)
+	now := time.Now()
+	failedUntil[Version{Major: 9, Minor: 0}] = now.Add(-time.Second) // expired
+	for i := 1; i < maxFailures+10; i++ {
+		failedUntil[Version{Major: 9, Minor: i}] = now.Add(time.Duration(i) * time.Second)
+	}
+	sweepFailures()
+	n := len(failedUntil)
+	_, soonest := failedUntil[Version{Major: 9, Minor: 1}]
+	_, latest := failedUntil[Version{Major: 9, Minor: maxFailures + 9}]
+	failedUntil = previous
+	flightsMu.Unlock()
+
+	if n != maxFailures-1 || soonest || !latest {
+		t.Errorf("after the sweep: %d entries, soonest kept %v, latest kept %v", n, soonest, latest)
+	}
+}
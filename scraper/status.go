@@ -0,0 +1,128 @@
+package scraper
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is a snapshot of the scraper's health, for monitoring and
+// readiness probes.
+type Status struct {
+	Ready         bool      // a refresh has discovered versions or cached releases
+	LastRefresh   time.Time // end of the last refresh that completed without errors
+	LastAttempt   time.Time // end of the last refresh, successful or not
+	LastError     string    // errors of the last refresh; "" if it succeeded
+	LastErrorAt   time.Time // when LastError was recorded
+	UsingFallback bool      // versions are the built-in fallback list, not discovered
+	Discovered    int       // versions known
+	Cached        int       // of those, versions parsed and cached
+	FetchErrors   []VersionError
+	RateLimit     RateLimit
+	Completeness  Completeness
+}
+
+// Stale reports whether no refresh has succeeded for two refresh intervals,
+// e.g. because discovery keeps failing.
+func (s Status) Stale() bool {
+	return s.LastRefresh.IsZero() || time.Since(s.LastRefresh) > 2*GetConfig().RefreshInterval
+}
+
+// VersionError is the most recent failure to fetch one discovered version.
+// It is cleared once the version is fetched successfully.
+type VersionError struct {
+	Version Version
+	Error   string
+	At      time.Time
+}
+
+var (
+	statusMu      sync.Mutex
+	ready         bool
+	lastRefresh   time.Time
+	lastAttempt   time.Time
+	lastError     string
+	lastErrorAt   time.Time
+	usingFallback bool
+	// versionErrors holds failures of refresh, backfill and webhook
+	// fetches. Those only request discovered versions or ones named by an
+	// authenticated caller, so unlike on-demand fetches the map stays bounded.
+	versionErrors = make(map[Version]VersionError)
+)
+
+// GetStatus returns the current scraper status.
+func GetStatus() Status {
+	c := GetCompleteness()
+	statusMu.Lock()
+	s := Status{
+		Ready:         ready,
+		LastRefresh:   lastRefresh,
+		LastAttempt:   lastAttempt,
+		LastError:     lastError,
+		LastErrorAt:   lastErrorAt,
+		UsingFallback: usingFallback,
+		Discovered:    c.Total,
+		Cached:        c.Cached,
+		FetchErrors:   make([]VersionError, 0, len(versionErrors)),
+		RateLimit:     GetRateLimit(),
+		Completeness:  c,
+	}
+	for _, e := range versionErrors {
+		s.FetchErrors = append(s.FetchErrors, e)
+	}
+	statusMu.Unlock()
+
+	sort.Slice(s.FetchErrors, func(i, j int) bool {
+		return s.FetchErrors[j].Version.Less(s.FetchErrors[i].Version)
+	})
+	return s
+}
+
+// IsReady reports whether a refresh has discovered versions or cached
+// releases. A refresh that failed outright, serving at most the fallback
+// version list, does not make the scraper ready.
+func IsReady() bool {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	return ready
+}
+
+// recordRefresh notes the outcome of a refresh; err is nil on success, and
+// served reports whether it left versions or releases to serve.
+func recordRefresh(err error, served bool) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	now := time.Now()
+	if served {
+		ready = true
+	}
+	lastAttempt = now
+	if err != nil {
+		lastError = err.Error()
+		lastErrorAt = now
+		return
+	}
+	lastRefresh = now
+	lastError = ""
+}
+
+// setUsingFallback records whether the version list is fallbackVersions.
+func setUsingFallback(fallback bool) {
+	statusMu.Lock()
+	usingFallback = fallback
+	statusMu.Unlock()
+}
+
+// recordFetchError remembers the failure of a refresh or backfill fetch.
+func recordFetchError(version Version, err error) {
+	statusMu.Lock()
+	versionErrors[version] = VersionError{Version: version, Error: err.Error(), At: time.Now()}
+	statusMu.Unlock()
+}
+
+// clearFetchError forgets any failure recorded for version.
+func clearFetchError(version Version) {
+	statusMu.Lock()
+	delete(versionErrors, version)
+	statusMu.Unlock()
+}
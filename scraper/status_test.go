@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRefreshReadiness(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	previous := GetConfig()
+	cfg := DefaultConfig()
+	cfg.RawBaseURL, cfg.APIBaseURL = srv.URL, srv.URL
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	statusMu.Lock()
+	wasReady, wasFallback := ready, usingFallback
+	ready = false
+	statusMu.Unlock()
+	versionsMu.Lock()
+	versions := availableVersions
+	availableVersions = nil
+	versionsMu.Unlock()
+	t.Cleanup(func() {
+		Configure(previous)
+		statusMu.Lock()
+		ready, usingFallback = wasReady, wasFallback
+		statusMu.Unlock()
+		versionsMu.Lock()
+		availableVersions = versions
+		versionsMu.Unlock()
+		mu.Lock()
+		delete(cached, Version{Major: 1, Minor: 3})
+		mu.Unlock()
+		for _, v := range fallbackVersions {
+			clearFetchError(v)
+		}
+		rebuildIndex()
+	})
+
+	// Discovery fails and so does every fetch of the fallback versions
+	if _, err := Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh succeeded against a failing server")
+	}
+	if s := GetStatus(); s.Ready || !s.UsingFallback || IsReady() {
+		t.Errorf("after a failed refresh: ready %v, fallback %v", s.Ready, s.UsingFallback)
+	}
+
+	dir := t.TempDir()
+	md := "## Thank you\n\n* [@a (A)](https://github.com/a): x [PR #1](https://github.com/o/r/pull/1)\n"
+	if err := os.WriteFile(filepath.Join(dir, "v1_3.md"), []byte(md), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Dir = dir
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if !IsReady() {
+		t.Error("not ready after a refresh that discovered versions")
+	}
+}
@@ -0,0 +1,274 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Store persists parsed releases and the discovered version list so the
+// cache can be served warm after a restart.
+type Store interface {
+	LoadVersions() ([]Version, error)
+	SaveVersions(versions []Version) error
+	LoadReleases() (map[Version]Release, error)
+	SaveRelease(r Release) error
+}
+
+var (
+	storeMu sync.RWMutex
+	store   Store
+	// storeDir is the Config.CacheDir the store was last opened for.
+	storeDir string
+)
+
+// openCacheDir installs a FileStore in dir, or no Store for an empty dir,
+// unless Configure already opened dir. A cache that fails to load is logged
+// and otherwise ignored, so a damaged cache doesn't keep the site down.
+func openCacheDir(dir string) {
+	storeMu.Lock()
+	same := dir == storeDir
+	storeDir = dir
+	storeMu.Unlock()
+	if same {
+		return
+	}
+	var s Store
+	if dir != "" {
+		s = NewFileStore(dir)
+	}
+	if err := SetStore(s); err != nil {
+		log.Printf("scraper: failed to load cache from %s: %v", dir, err)
+	}
+}
+
+// SetStore installs s as the persistence layer and warms the in-memory
+// cache from whatever it already holds. Passing nil disables persistence.
+func SetStore(s Store) error {
+	storeMu.Lock()
+	store = s
+	storeMu.Unlock()
+	if s == nil {
+		return nil
+	}
+
+	versions, err := s.LoadVersions()
+	if err != nil {
+		return err
+	}
+	releases, err := s.LoadReleases()
+	if err != nil {
+		return err
+	}
+
+	if len(versions) > 0 {
+		versionsMu.Lock()
+		if len(availableVersions) == 0 {
+			availableVersions = versions
+		}
+		versionsMu.Unlock()
+	}
+
+	mu.Lock()
+	for id, r := range releases {
+		if _, ok := cached[id]; !ok {
+			cached[id] = r
+		}
+	}
+	mu.Unlock()
+	for _, r := range releases {
+		rememberEnriched(r)
+	}
+	rebuildIndex()
+
+	log.Printf("scraper: loaded %d versions and %d releases from store", len(versions), len(releases))
+	return nil
+}
+
+func currentStore() Store {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return store
+}
+
+// persistRelease writes r to the configured store, if any.
+func persistRelease(r Release) {
+	s := currentStore()
+	if s == nil {
+		return
+	}
+	if err := s.SaveRelease(r); err != nil {
+		log.Printf("scraper: failed to persist %s: %v", r.Version, err)
+	}
+}
+
+// persistVersions writes the version list to the configured store, if any.
+func persistVersions(versions []Version) {
+	s := currentStore()
+	if s == nil {
+		return
+	}
+	if err := s.SaveVersions(versions); err != nil {
+		log.Printf("scraper: failed to persist versions: %v", err)
+	}
+}
+
+// FileStore is a Store backed by JSON files in a directory:
+// versions.json holds the version list and releases/<id>.json one release each.
+type FileStore struct {
+	dir string
+}
+
+// storeFormat versions the files a FileStore writes. They use the JSON
+// encoding of Release and Version, which the public API shares, so bump it
+// whenever that encoding changes: files of any other format are discarded
+// when loaded, and their releases fetched again. Releases parsed by another
+// parserVersion are discarded the same way.
+const storeFormat = 2
+
+// storedVersions and storedRelease are the contents of versions.json and
+// of a release file.
+type storedVersions struct {
+	Format   int       `json:"format"`
+	Versions []Version `json:"versions"`
+}
+
+type storedRelease struct {
+	Format  int     `json:"format"`
+	Parser  int     `json:"parser"` // parserVersion
+	Release Release `json:"release"`
+}
+
+// NewFileStore returns a FileStore rooted at dir. The directory is created
+// on first write.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (fs *FileStore) versionsPath() string {
+	return filepath.Join(fs.dir, "versions.json")
+}
+
+func (fs *FileStore) releasesDir() string {
+	return filepath.Join(fs.dir, "releases")
+}
+
+// LoadVersions returns the persisted version list, or nil if none was saved
+// in the current format.
+func (fs *FileStore) LoadVersions() ([]Version, error) {
+	var stored storedVersions
+	ok, err := readStored(fs.versionsPath(), &stored)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil || !ok {
+		return nil, err
+	}
+	return stored.Versions, nil
+}
+
+// SaveVersions replaces the persisted version list.
+func (fs *FileStore) SaveVersions(versions []Version) error {
+	return writeJSON(fs.versionsPath(), storedVersions{Format: storeFormat, Versions: versions})
+}
+
+// LoadReleases returns every persisted release keyed by version.
+func (fs *FileStore) LoadReleases() (map[Version]Release, error) {
+	entries, err := os.ReadDir(fs.releasesDir())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[Version]Release{}, nil
+		}
+		return nil, err
+	}
+
+	releases := make(map[Version]Release, len(entries))
+	stale := 0
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(fs.releasesDir(), e.Name())
+		var stored storedRelease
+		ok, err := readStored(path, &stored)
+		if err != nil {
+			log.Printf("scraper: skipping unreadable cache file %s: %v", e.Name(), err)
+			continue
+		}
+		if !ok || stored.Parser != parserVersion {
+			stale++
+			os.Remove(path)
+			continue
+		}
+		if r := stored.Release; !r.Version.IsZero() {
+			releases[r.Version] = r
+		}
+	}
+	if stale > 0 {
+		log.Printf("scraper: discarded %d cached releases of another format or parser version", stale)
+	}
+	return releases, nil
+}
+
+// SaveRelease writes r to releases/<version>.json.
+func (fs *FileStore) SaveRelease(r Release) error {
+	if r.Version.IsZero() {
+		return fmt.Errorf("invalid release version %q", r.Version)
+	}
+	return writeJSON(filepath.Join(fs.releasesDir(), r.Version.ID()+".json"), storedRelease{Format: storeFormat, Parser: parserVersion, Release: r})
+}
+
+// readStored reads a file written in storeFormat into v. It reports false,
+// leaving v alone, if the file holds another format.
+func readStored(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	var header struct {
+		Format int `json:"format"`
+	}
+	if json.Unmarshal(data, &header) != nil || header.Format != storeFormat {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSON writes v to path atomically via a temp file and rename so a
+// crash mid-write never leaves a truncated cache entry behind.
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
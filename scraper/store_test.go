@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigureCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	v := Version{Major: 5, Minor: 1}
+	if err := NewFileStore(dir).SaveRelease(Release{Version: v, DisplayName: "5.1"}); err != nil {
+		t.Fatal(err)
+	}
+	previous := GetConfig()
+	cfg := DefaultConfig()
+	cfg.CacheDir = dir
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		Configure(previous)
+		mu.Lock()
+		delete(cached, v)
+		mu.Unlock()
+		rebuildIndex()
+	})
+	if _, ok := GetIndex().Release(v); !ok {
+		t.Fatal("release in the cache directory not loaded by Configure")
+	}
+
+	cfg.CacheDir = ""
+	if err := Configure(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if currentStore() != nil {
+		t.Error("store still open after Configure without a cache directory")
+	}
+}
+
+func TestFileStoreFormat(t *testing.T) {
+	dir := t.TempDir()
+	fs := NewFileStore(dir)
+	v := Version{Major: 1, Minor: 2}
+	if err := fs.SaveRelease(Release{Version: v, DisplayName: "1.2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.SaveVersions([]Version{v}); err != nil {
+		t.Fatal(err)
+	}
+	// A release file in another format, e.g. a bare Release
+	old := filepath.Join(dir, "releases", "v1_1.json")
+	if err := os.WriteFile(old, []byte(`{"version": "1.1", "display_name": "1.1"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// One parsed by another parser version
+	reparse := filepath.Join(dir, "releases", "v1_0.json")
+	if err := os.WriteFile(reparse, []byte(fmt.Sprintf(`{"format": %d, "parser": %d, "release": {"version": "1.0"}}`, storeFormat, parserVersion-1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := fs.LoadReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 1 || releases[v].DisplayName != "1.2" {
+		t.Errorf("LoadReleases = %v", releases)
+	}
+	for _, path := range []string{old, reparse} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("stale release file %s not removed", filepath.Base(path))
+		}
+	}
+	if versions, err := fs.LoadVersions(); err != nil || len(versions) != 1 || versions[0] != v {
+		t.Errorf("LoadVersions = %v, %v", versions, err)
+	}
+
+	if err := os.WriteFile(fs.versionsPath(), []byte(`["1.2"]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if versions, err := fs.LoadVersions(); err != nil || versions != nil {
+		t.Errorf("LoadVersions of another format = %v, %v", versions, err)
+	}
+}
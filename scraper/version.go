@@ -0,0 +1,154 @@
+package scraper
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Version is a VS Code release number: 1.109, or 1.109.1 for a recovery
+// release. Release notes are published per minor version, so recovery
+// releases share the notes of their Base version. The zero Version is
+// invalid and stands for "none".
+//
+// Versions are comparable and can be used as map keys. They marshal as
+// text in the String form and parse from any of the forms ParseVersion
+// accepts.
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses "1.109", "v1.109", "v1_109" (the release-notes file
+// name) and the same forms with a recovery component, e.g. "1.109.1".
+func ParseVersion(s string) (Version, error) {
+	t := strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	var parts []string
+	if strings.Contains(t, "_") {
+		parts = strings.Split(t, "_")
+	} else {
+		parts = strings.Split(t, ".")
+	}
+	if len(parts) < 2 || len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	var n [3]int
+	for i, p := range parts {
+		// Digits only: no signs, spaces or overflow.
+		if p == "" || len(p) > 9 || strings.Trim(p, "0123456789") != "" {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		n[i], _ = strconv.Atoi(p)
+	}
+	v := Version{Major: n[0], Minor: n[1], Patch: n[2]}
+	if v.IsZero() {
+		return Version{}, fmt.Errorf("invalid version %q", s)
+	}
+	return v, nil
+}
+
+// mustParseVersion is ParseVersion for literals known to be valid.
+func mustParseVersion(s string) Version {
+	v, err := ParseVersion(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IsZero reports whether v is the zero Version.
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// Base returns the release whose notes cover v, i.e. v without its
+// recovery component.
+func (v Version) Base() Version {
+	return Version{Major: v.Major, Minor: v.Minor}
+}
+
+// IsRecovery reports whether v is a recovery release such as 1.109.1.
+func (v Version) IsRecovery() bool {
+	return v.Patch > 0
+}
+
+// Compare returns -1, 0 or +1 depending on whether v sorts before, equal
+// to or after w.
+func (v Version) Compare(w Version) int {
+	switch {
+	case v.Major != w.Major:
+		return cmpInt(v.Major, w.Major)
+	case v.Minor != w.Minor:
+		return cmpInt(v.Minor, w.Minor)
+	default:
+		return cmpInt(v.Patch, w.Patch)
+	}
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Less reports whether v is an older release than w.
+func (v Version) Less(w Version) bool {
+	return v.Compare(w) < 0
+}
+
+// String returns the plain form, "1.109" or "1.109.1"; "" for the zero Version.
+func (v Version) String() string {
+	if v.IsZero() {
+		return ""
+	}
+	if v.Patch > 0 {
+		return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	}
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Tag returns the prefixed form used in the UI, "v1.109".
+func (v Version) Tag() string {
+	if v.IsZero() {
+		return ""
+	}
+	return "v" + v.String()
+}
+
+// ID returns the release-notes identifier, "v1_109", as used in file names
+// and URLs.
+func (v Version) ID() string {
+	if v.IsZero() {
+		return ""
+	}
+	return "v" + strings.ReplaceAll(v.String(), ".", "_")
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is the
+// zero Version.
+func (v *Version) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*v = Version{}
+		return nil
+	}
+	parsed, err := ParseVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// sortNewestFirst sorts versions in place, newest first.
+func sortNewestFirst(versions []Version) {
+	slices.SortFunc(versions, func(a, b Version) int { return b.Compare(a) })
+}
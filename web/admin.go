@@ -0,0 +1,180 @@
+package web
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// refreshTimeout bounds a refresh started by the admin endpoint, which
+// outlives the request that triggered it.
+const refreshTimeout = 5 * time.Minute
+
+// maxWebhookBody is GitHub's maximum webhook payload size.
+const maxWebhookBody = 25 << 20
+
+// RefreshResult is the response of the admin refresh endpoint and the
+// webhook receiver.
+type RefreshResult struct {
+	Versions []scraper.Version `json:"versions,omitempty"` // versions refreshed, or queued for refresh
+	Error    string            `json:"error,omitempty"`
+	Message  string            `json:"message,omitempty"`
+}
+
+// AdminRefreshHandler triggers a refresh on POST /api/admin/refresh, or of
+// a single release with ?version=1.109. Requests must carry the configured
+// admin token as "Authorization: Bearer <token>"; without a configured
+// token the endpoint does not exist.
+func AdminRefreshHandler(w http.ResponseWriter, r *http.Request) {
+	token := scraper.GetConfig().AdminToken
+	if token == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var version scraper.Version
+	if v := r.URL.Query().Get("version"); v != "" {
+		var err error
+		if version, err = scraper.ParseVersion(v); err != nil {
+			http.Error(w, "Invalid version", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// The refresh carries on if the caller hangs up
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), refreshTimeout)
+	defer cancel()
+
+	var result RefreshResult
+	var err error
+	if version.IsZero() {
+		var releases []scraper.Release
+		releases, err = scraper.Refresh(ctx)
+		for _, rel := range releases {
+			result.Versions = append(result.Versions, rel.Version)
+		}
+	} else {
+		result.Versions = []scraper.Version{version.Base()}
+		err = scraper.RefreshVersions(ctx, result.Versions)
+	}
+	log.Printf("admin: refresh requested from %s", r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		result.Error = err.Error()
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// pushEvent is the part of a GitHub push webhook payload the receiver uses.
+type pushEvent struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"` // SHA of the head commit after the push
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Commits []struct {
+		Added    []string `json:"added"`
+		Modified []string `json:"modified"`
+	} `json:"commits"`
+}
+
+// GitHubWebhookHandler receives GitHub push webhooks on /api/webhook/github
+// and refreshes only the release notes the push added or changed. Payloads
+// must be signed with the configured webhook secret (X-Hub-Signature-256);
+// without a secret the endpoint does not exist. The refresh is queued on the
+// background Runner, since GitHub gives up on slow deliveries, and reads the
+// notes at the pushed commit rather than the branch, which the raw file CDN
+// caches for minutes.
+func GitHubWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	secret := scraper.GetConfig().WebhookSecret
+	if secret == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "Payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !validSignature(secret, body, r.Header.Get("X-Hub-Signature-256")) {
+		http.Error(w, "Invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch event := r.Header.Get("X-GitHub-Event"); event {
+	case "ping":
+		json.NewEncoder(w).Encode(RefreshResult{Message: "pong"})
+		return
+	case "push":
+	default:
+		json.NewEncoder(w).Encode(RefreshResult{Message: "ignored " + event + " event"})
+		return
+	}
+
+	var push pushEvent
+	if err := json.Unmarshal(body, &push); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+	var paths []string
+	for _, c := range push.Commits {
+		paths = append(paths, c.Added...)
+		paths = append(paths, c.Modified...)
+	}
+	versions := scraper.ChangedVersions(push.Repository.FullName, push.Ref, paths)
+	if len(versions) == 0 {
+		json.NewEncoder(w).Encode(RefreshResult{Message: "no release notes changed"})
+		return
+	}
+
+	log.Printf("webhook: push to %s changed %d release notes", push.Repository.FullName, len(versions))
+	if !scraper.QueueRefresh(push.After, versions) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(RefreshResult{Versions: versions, Error: "refresh queue unavailable"})
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(RefreshResult{Versions: versions, Message: "refresh queued"})
+}
+
+// validSignature checks a GitHub "sha256=<hex>" HMAC of body.
+func validSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
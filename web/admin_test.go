@@ -0,0 +1,37 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestValidSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	good := hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"good", "sha256=" + good, true},
+		{"bad", "sha256=" + strings.Repeat("0", len(good)), false},
+		{"not hex", "sha256=xyz", false},
+		{"missing", "", false},
+		{"wrong prefix", "sha1=" + good, false},
+		{"no prefix", good, false},
+	}
+	for _, tt := range tests {
+		if got := validSignature("secret", body, tt.header); got != tt.want {
+			t.Errorf("%s signature: validSignature = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if validSignature("other", body, "sha256="+good) {
+		t.Error("signature valid with another secret")
+	}
+}
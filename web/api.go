@@ -0,0 +1,341 @@
+package web
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// The page size of /api/v1 lists, unless per_page asks for fewer or more.
+const (
+	apiPerPage    = 50
+	apiMaxPerPage = 100
+)
+
+// openAPIDoc describes /api/v1; keep it in step with the types below.
+//
+//go:embed openapi.json
+var openAPIDoc []byte
+
+// APIError is the body of every /api/v1 error, inside {"error": ...}.
+type APIError struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"` // e.g. "not_found", "invalid_parameter"
+	Message string `json:"message"`
+}
+
+// APIPagination describes the page of a list response.
+type APIPagination struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	Total      int `json:"total"`
+	TotalPages int `json:"total_pages"`
+}
+
+// APIReleaseSummary is a release in the /api/v1/releases list.
+type APIReleaseSummary struct {
+	Version       scraper.Version `json:"version"`
+	DisplayName   string          `json:"display_name"`
+	Recovery      scraper.Version `json:"recovery,omitzero"`
+	Date          time.Time       `json:"date,omitzero"`
+	DateMonthOnly bool            `json:"date_month_only,omitempty"`
+	Contributors  int             `json:"contributors"`
+	PRs           int             `json:"prs"`
+	IssueTracking int             `json:"issue_tracking"`
+	Documentation int             `json:"documentation"`
+	Localization  int             `json:"localization"`
+}
+
+// APIContributor is a contributor's history across releases.
+type APIContributor struct {
+	GitHubUser    string                  `json:"github_user"`
+	Name          string                  `json:"name"`
+	AvatarURL     string                  `json:"avatar_url"`
+	TotalPRs      int                     `json:"total_prs"`
+	TotalDocsPRs  int                     `json:"total_docs_prs"`
+	ReleaseCount  int                     `json:"release_count"`
+	FirstRelease  scraper.Version         `json:"first_release,omitzero"`
+	LatestRelease scraper.Version         `json:"latest_release,omitzero"`
+	Kudos         int                     `json:"kudos"`
+	Releases      []APIContributorRelease `json:"releases"` // newest first
+}
+
+// APIContributorRelease is what a contributor was thanked for in one
+// release.
+type APIContributorRelease struct {
+	Version       scraper.Version `json:"version"`
+	Date          time.Time       `json:"date,omitzero"`
+	PRs           []scraper.PR    `json:"prs"`
+	DocsPRs       []scraper.PR    `json:"docs_prs"`
+	IssueTracking bool            `json:"issue_tracking"`
+}
+
+// APIv1Handler serves the versioned JSON API under /api/v1/:
+//
+//	GET /api/v1/releases                         releases, newest first
+//	GET /api/v1/releases/{version}               one release with its contributors
+//	GET /api/v1/contributors/{user}              a contributor's history
+//	GET /api/v1/leaderboard?tab=&from=&to=&repo= a ranked leaderboard
+//	GET /api/v1/openapi.json                     the OpenAPI document
+//
+// Lists take page and per_page and are wrapped as {"data": [...],
+// "pagination": {...}}, single objects as {"data": {...}} and errors as
+// {"error": {...}}.
+func APIv1Handler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeAPIError(w, http.StatusMethodNotAllowed, "method_not_allowed", "only GET is supported")
+		return
+	}
+	setCompletenessHeader(w)
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+	resource, arg, _ := strings.Cut(path, "/")
+	switch {
+	case resource == "openapi.json" && arg == "":
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPIDoc)
+	case resource == "releases" && arg == "":
+		apiReleases(w, r)
+	case resource == "releases":
+		apiRelease(w, arg)
+	case resource == "contributors" && arg != "":
+		apiContributor(w, arg)
+	case resource == "leaderboard" && arg == "":
+		apiLeaderboard(w, r)
+	default:
+		writeAPIError(w, http.StatusNotFound, "not_found", "no such endpoint: "+r.URL.Path)
+	}
+}
+
+func apiReleases(w http.ResponseWriter, r *http.Request) {
+	releases := scraper.GetIndex().Releases()
+	page, ok := paginate(w, r, len(releases))
+	if !ok {
+		return
+	}
+	summaries := []APIReleaseSummary{}
+	for _, rel := range releases[page.start():page.end()] {
+		s := APIReleaseSummary{
+			Version:       rel.Version,
+			DisplayName:   rel.DisplayName,
+			Recovery:      rel.Recovery,
+			Date:          rel.Date,
+			DateMonthOnly: rel.DateMonthOnly,
+			Contributors:  len(rel.Contributors),
+			IssueTracking: len(rel.IssueTracking),
+			Documentation: len(rel.Documentation),
+			Localization:  len(rel.Localization),
+		}
+		for _, c := range rel.Contributors {
+			s.PRs += len(c.PRs)
+		}
+		summaries = append(summaries, s)
+	}
+	writeAPIList(w, summaries, page)
+}
+
+func apiRelease(w http.ResponseWriter, arg string) {
+	version, err := scraper.ParseVersion(arg)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid_parameter", "invalid version "+strconv.Quote(arg))
+		return
+	}
+	version = version.Base()
+	rel, ok := lookupRelease(scraper.GetIndex(), version)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "not_found", "no release "+version.String())
+		return
+	}
+
+	// Lists are always arrays, never null
+	if rel.Contributors == nil {
+		rel.Contributors = []scraper.Contributor{}
+	}
+	if rel.IssueTracking == nil {
+		rel.IssueTracking = []scraper.Contributor{}
+	}
+	if rel.Documentation == nil {
+		rel.Documentation = []scraper.Contributor{}
+	}
+	if rel.Localization == nil {
+		rel.Localization = []scraper.Translator{}
+	}
+	writeAPIData(w, rel)
+}
+
+// lookupRelease returns a release from index, or fetches it if it is not
+// cached yet. Only versions the release notes repository is known to have
+// are fetched.
+func lookupRelease(index *scraper.Index, version scraper.Version) (scraper.Release, bool) {
+	if rel, ok := index.Release(version); ok {
+		return rel, true
+	}
+	for _, v := range scraper.GetAvailableVersions() {
+		if v == version.Base() {
+			return scraper.GetRelease(version)
+		}
+	}
+	return scraper.Release{}, false
+}
+
+func apiContributor(w http.ResponseWriter, username string) {
+	if !validUser.MatchString(username) {
+		writeAPIError(w, http.StatusBadRequest, "invalid_parameter", "invalid GitHub login "+strconv.Quote(username))
+		return
+	}
+	history := scraper.GetContributorHistory(username)
+	if history == nil {
+		writeAPIError(w, http.StatusNotFound, "not_found", "no contributor "+username)
+		return
+	}
+
+	c := APIContributor{
+		GitHubUser:    history.GitHubUser,
+		Name:          history.Name,
+		AvatarURL:     history.AvatarURL,
+		TotalPRs:      history.TotalPRs,
+		TotalDocsPRs:  history.TotalDocsPRs,
+		ReleaseCount:  history.ReleaseCount,
+		FirstRelease:  history.FirstRelease,
+		LatestRelease: history.LatestRelease,
+		Releases:      []APIContributorRelease{},
+	}
+	issueReleases := make(map[scraper.Version]bool, len(history.IssueTrackingReleases))
+	for _, v := range history.IssueTrackingReleases {
+		issueReleases[v] = true
+	}
+	for _, rel := range scraper.GetIndex().Releases() {
+		prs, docs := history.PRsByRelease[rel.Version], history.DocsPRsByRelease[rel.Version]
+		if len(prs) == 0 && len(docs) == 0 && !issueReleases[rel.Version] {
+			continue
+		}
+		if prs == nil {
+			prs = []scraper.PR{}
+		}
+		if docs == nil {
+			docs = []scraper.PR{}
+		}
+		c.Releases = append(c.Releases, APIContributorRelease{
+			Version:       rel.Version,
+			Date:          rel.Date,
+			PRs:           prs,
+			DocsPRs:       docs,
+			IssueTracking: issueReleases[rel.Version],
+		})
+	}
+
+	kudosMu.RLock()
+	c.Kudos = kudosStore[scraper.CanonicalUser(history.GitHubUser)]
+	kudosMu.RUnlock()
+	writeAPIData(w, c)
+}
+
+func apiLeaderboard(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	tab := q.Get("tab")
+	switch tab {
+	case "":
+		tab = "prs"
+	case "prs", "releases", "issues", "docs", "translations":
+	default:
+		writeAPIError(w, http.StatusBadRequest, "invalid_parameter",
+			"tab must be one of prs, releases, issues, docs or translations")
+		return
+	}
+
+	// Inclusive dates, as on the leaderboard page, but invalid ones are an
+	// error rather than ignored
+	var from, to time.Time
+	if s := q.Get("from"); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid_parameter", "from must be a date like 2025-01-31")
+			return
+		}
+		from = t
+	}
+	if s := q.Get("to"); s != "" {
+		t, err := time.Parse(dateLayout, s)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, "invalid_parameter", "to must be a date like 2025-12-31")
+			return
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+
+	repo := q.Get("repo")
+	if repo != "" && !validRepo.MatchString(repo) {
+		writeAPIError(w, http.StatusBadRequest, "invalid_parameter", "repo must be owner/name or name")
+		return
+	}
+
+	entries := leaderboardEntries(scraper.GetIndex().Between(from, to).ForRepo(repo), tab)
+	page, ok := paginate(w, r, len(entries))
+	if !ok {
+		return
+	}
+	writeAPIList(w, append([]LeaderboardEntry{}, entries[page.start():page.end()]...), page)
+}
+
+// start and end bound the page within the list. Pages past the end are
+// empty; they are checked before multiplying so a huge page cannot overflow.
+func (p APIPagination) start() int {
+	if p.Page > p.TotalPages {
+		return p.Total
+	}
+	return (p.Page - 1) * p.PerPage
+}
+
+func (p APIPagination) end() int {
+	return min(p.start()+p.PerPage, p.Total)
+}
+
+// paginate reads page and per_page for a list of total items. It writes an
+// error and returns false if either is invalid; pages past the end are
+// empty, not an error.
+func paginate(w http.ResponseWriter, r *http.Request, total int) (APIPagination, bool) {
+	p := APIPagination{Page: 1, PerPage: apiPerPage, Total: total}
+	if s := r.URL.Query().Get("page"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			writeAPIError(w, http.StatusBadRequest, "invalid_parameter", "page must be a positive integer")
+			return p, false
+		}
+		p.Page = n
+	}
+	if s := r.URL.Query().Get("per_page"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > apiMaxPerPage {
+			writeAPIError(w, http.StatusBadRequest, "invalid_parameter",
+				"per_page must be between 1 and "+strconv.Itoa(apiMaxPerPage))
+			return p, false
+		}
+		p.PerPage = n
+	}
+	p.TotalPages = (total + p.PerPage - 1) / p.PerPage
+	return p, true
+}
+
+func writeAPIData(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func writeAPIList(w http.ResponseWriter, data interface{}, page APIPagination) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data, "pagination": page})
+}
+
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]APIError{
+		"error": {Status: status, Code: code, Message: message},
+	})
+}
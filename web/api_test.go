@@ -0,0 +1,88 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	tests := []struct {
+		query      string
+		total      int
+		start, end int
+	}{
+		{"", 120, 0, 50},
+		{"page=3", 120, 100, 120},
+		{"page=4", 120, 120, 120},
+		{"page=2&per_page=100", 120, 100, 120},
+		{"page=9223372036854775807", 120, 120, 120},
+		{"page=9223372036854775807&per_page=100", 0, 0, 0},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/api/v1/releases?"+tt.query, nil)
+		p, ok := paginate(httptest.NewRecorder(), r, tt.total)
+		if !ok || p.start() != tt.start || p.end() != tt.end {
+			t.Errorf("%q of %d: ok %v, items [%d:%d], want [%d:%d]", tt.query, tt.total, ok, p.start(), p.end(), tt.start, tt.end)
+		}
+	}
+}
+
+func TestAPIv1HugePage(t *testing.T) {
+	for _, path := range []string{"/api/v1/releases", "/api/v1/leaderboard"} {
+		w := httptest.NewRecorder()
+		APIv1Handler(w, httptest.NewRequest("GET", path+"?page="+strconv.Itoa(int(^uint(0)>>1)), nil))
+		var body struct {
+			Data []json.RawMessage `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&body); w.Code != http.StatusOK || err != nil || len(body.Data) != 0 {
+			t.Errorf("%s: status %d, %d items, %v", path, w.Code, len(body.Data), err)
+		}
+	}
+}
+
+// TestGraphQLIntrospection runs the introspection query GraphiQL and code
+// generators send, which must fit within the /api/graphql limits.
+func TestGraphQLIntrospection(t *testing.T) {
+	query := `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    types { ...FullType }
+    directives { name locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) { name description args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+	body, _ := json.Marshal(map[string]string{"query": query})
+	w := httptest.NewRecorder()
+	GraphQLHandler(w, httptest.NewRequest("POST", "/api/graphql", bytes.NewReader(body)))
+	var resp struct {
+		Data struct {
+			Schema struct {
+				QueryType struct{ Name string }   `json:"queryType"`
+				Types     []struct{ Name string } `json:"types"`
+			} `json:"__schema"`
+		} `json:"data"`
+		Errors []struct{ Message string } `json:"errors"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || w.Code != http.StatusOK || len(resp.Errors) > 0 {
+		t.Fatalf("status %d, errors %v, %v", w.Code, resp.Errors, err)
+	}
+	if resp.Data.Schema.QueryType.Name != "Query" || len(resp.Data.Schema.Types) < 5 {
+		t.Errorf("schema %+v", resp.Data.Schema)
+	}
+}
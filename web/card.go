@@ -63,30 +63,14 @@ type contributorStats struct {
 }
 
 func getContributorStats(username string) contributorStats {
-	versions := scraper.GetAvailableVersions()
 	stats := contributorStats{}
-	releaseSet := make(map[string]bool)
-
-	for _, v := range versions {
-		rel, ok := scraper.GetRelease(v.ID)
-		if !ok {
-			continue
-		}
-		for _, c := range rel.Contributors {
-			if strings.EqualFold(c.GitHubUser, username) {
-				stats.totalPRs += len(c.PRs)
-				releaseSet[v.ID] = true
-				if c.Name != "" {
-					stats.name = c.Name
-				}
-				if c.AvatarURL != "" {
-					stats.avatarURL = c.AvatarURL
-				}
-			}
-		}
+	if h := scraper.GetIndex().Contributor(username); h != nil {
+		stats.name = h.Name
+		stats.totalPRs = h.TotalPRs
+		stats.releases = h.ReleaseCount
+		stats.avatarURL = h.AvatarURL
 	}
 
-	stats.releases = len(releaseSet)
 	if stats.name == "" {
 		stats.name = username
 	}
@@ -0,0 +1,134 @@
+package web
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// CompareResult is the /api/compare response: the comparison plus the
+// releases in range that are still loading and are left out of it.
+type CompareResult struct {
+	scraper.Comparison
+	Loading []scraper.Version `json:"loading"`
+}
+
+// ComparePageData is the view model for the release comparison page.
+type ComparePageData struct {
+	FromVersions []VersionOption
+	ToVersions   []VersionOption
+	Result       CompareResult
+	Loading      bool
+	Completeness scraper.Completeness
+}
+
+// compareReleases compares the releases named by ?from= and ?to=, in any
+// form ParseVersion accepts. To defaults to the newest release and from to
+// the one before to; reversed versions are swapped. From and to are fetched
+// if need be, but releases between them that are not yet cached are only
+// listed as loading, so one request cannot set off a fetch of every release
+// in range. On failure it returns an HTTP status and message.
+func compareReleases(r *http.Request) (CompareResult, int, string) {
+	available := scraper.GetAvailableVersions()
+	var from, to scraper.Version
+	var err error
+	if v := r.URL.Query().Get("from"); v != "" {
+		if from, err = scraper.ParseVersion(v); err != nil {
+			return CompareResult{}, http.StatusBadRequest, "Invalid from version"
+		}
+	}
+	if v := r.URL.Query().Get("to"); v != "" {
+		if to, err = scraper.ParseVersion(v); err != nil {
+			return CompareResult{}, http.StatusBadRequest, "Invalid to version"
+		}
+	}
+	from, to = from.Base(), to.Base()
+	if to.IsZero() && len(available) > 0 {
+		to = available[0]
+	}
+	if from.IsZero() {
+		// available is newest first
+		for _, v := range available {
+			if v.Less(to) {
+				from = v
+				break
+			}
+		}
+	}
+	if to.Less(from) {
+		from, to = to, from
+	}
+	if from == to {
+		return CompareResult{}, http.StatusBadRequest, "Choose two different releases"
+	}
+
+	index := scraper.GetIndex()
+	for _, v := range []scraper.Version{from, to} {
+		if _, ok := lookupRelease(index, v); !ok {
+			return CompareResult{}, http.StatusNotFound, "No release " + v.String()
+		}
+	}
+	index = scraper.GetIndex()
+	result := CompareResult{Loading: []scraper.Version{}}
+	for _, v := range available {
+		if v.Less(from) || to.Less(v) {
+			continue
+		}
+		if _, ok := index.Release(v); !ok {
+			result.Loading = append(result.Loading, v)
+		}
+	}
+	result.Comparison = index.Compare(from, to)
+	return result, http.StatusOK, ""
+}
+
+// CompareHandler renders /compare?from=v1_105&to=v1_109: who is new, who
+// returned and who dropped off between two releases, how each repository's
+// PRs changed and who grew the most.
+func CompareHandler(w http.ResponseWriter, r *http.Request) {
+	availableVersions := scraper.GetAvailableVersions()
+	if len(availableVersions) == 0 {
+		data := ComparePageData{Loading: true}
+		if err := templates.ExecuteTemplate(w, "compare.html", data); err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	result, status, msg := compareReleases(r)
+	if status != http.StatusOK {
+		http.Error(w, msg, status)
+		return
+	}
+	data := ComparePageData{
+		Result:       result,
+		Completeness: scraper.GetCompleteness(),
+	}
+	for _, v := range availableVersions {
+		data.FromVersions = append(data.FromVersions, VersionOption{ID: v.ID(), Display: v.String(), Selected: v == result.From})
+		data.ToVersions = append(data.ToVersions, VersionOption{ID: v.ID(), Display: v.String(), Selected: v == result.To})
+	}
+
+	if err := templates.ExecuteTemplate(w, "compare.html", data); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		log.Printf("Template error: %v", err)
+	}
+}
+
+// CompareAPIHandler is the JSON counterpart of CompareHandler.
+func CompareAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if len(scraper.GetAvailableVersions()) == 0 {
+		http.Error(w, "Release data is still loading", http.StatusServiceUnavailable)
+		return
+	}
+	result, status, msg := compareReleases(r)
+	if status != http.StatusOK {
+		http.Error(w, msg, status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	setCompletenessHeader(w)
+	json.NewEncoder(w).Encode(result)
+}
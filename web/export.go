@@ -0,0 +1,69 @@
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/vscode-contributor-website/export"
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// ExportHandler streams /api/export/{kind}.{format}, e.g.
+// /api/export/prs.csv?from=1.100&to=1.109&repo=microsoft/vscode-python.
+// Kinds are releases, contributors and prs; formats csv and jsonl. It
+// exports the cached releases; X-Data-Completeness tells whether that is
+// all of them.
+func ExportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/api/export/")
+	kind, format, ok := strings.Cut(name, ".")
+	if !ok || export.Columns(kind) == nil || !slices.Contains(export.Formats, format) {
+		http.Error(w, "Use /api/export/{releases,contributors,prs}.{csv,jsonl}", http.StatusNotFound)
+		return
+	}
+
+	q := r.URL.Query()
+	filter := export.Filter{Repo: q.Get("repo")}
+	var err error
+	if v := q.Get("from"); v != "" {
+		if filter.From, err = scraper.ParseVersion(v); err != nil {
+			http.Error(w, "Invalid from version", http.StatusBadRequest)
+			return
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if filter.To, err = scraper.ParseVersion(v); err != nil {
+			http.Error(w, "Invalid to version", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// e.g. vscode-prs-1.100-1.109.csv
+	filename := "vscode-" + kind
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		filename += fmt.Sprintf("-%s-%s", versionOrAll(filter.From), versionOrAll(filter.To))
+	}
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+format))
+	setCompletenessHeader(w)
+	if r.Method == "HEAD" {
+		return
+	}
+	if err := export.Write(w, scraper.GetIndex(), kind, format, filter); err != nil {
+		// Headers are gone by now; the client sees a truncated file
+		log.Printf("export: %s: %v", r.URL, err)
+	}
+}
+
+func versionOrAll(v scraper.Version) string {
+	if v.IsZero() {
+		return "all"
+	}
+	return v.String()
+}
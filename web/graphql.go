@@ -0,0 +1,392 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/vscode-contributor-website/graphql"
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// Limits on /api/graphql queries; see graphql.Schema.
+const (
+	graphqlMaxDepth      = 10
+	graphqlMaxComplexity = 25000
+	graphqlMaxFirst      = 100
+	maxGraphQLBody       = 1 << 20
+)
+
+// graphqlSchema mirrors scraper.Release, Contributor, PR and
+// ContributorHistory. Field names are the Go names in camel case.
+var graphqlSchema = newGraphQLSchema()
+
+// indexKey is the context key of the scraper.Index a query resolves from,
+// so that one query sees one consistent snapshot of the cache.
+type indexKey struct{}
+
+func queryIndex(ctx context.Context) *scraper.Index {
+	if ix, ok := ctx.Value(indexKey{}).(*scraper.Index); ok {
+		return ix
+	}
+	return scraper.GetIndex()
+}
+
+// contributorRelease is what a contributor was thanked for in one release.
+type contributorRelease struct {
+	Version       scraper.Version
+	PRs           []scraper.PR
+	DocsPRs       []scraper.PR
+	IssueTracking bool
+}
+
+func newGraphQLSchema() *graphql.Schema {
+	nonNull := graphql.NewNonNull
+	list := func(t graphql.Type) graphql.Type { return nonNull(graphql.NewList(nonNull(t))) }
+	str, integer, boolean := graphql.String, graphql.Int, graphql.Boolean
+	first := &graphql.Arg{Name: "first", Type: integer, Description: "Return at most this many, up to 100"}
+	repo := &graphql.Arg{Name: "repo", Type: str, Description: "Only pull requests to this repository, as \"owner/name\" or \"name\""}
+
+	query := &graphql.Object{Name: "Query"}
+	release := &graphql.Object{Name: "Release", Description: "A VS Code release and everyone its notes thank"}
+	contributor := &graphql.Object{Name: "Contributor", Description: "Someone thanked in one release"}
+	pr := &graphql.Object{Name: "PR", Description: "A pull request thanked in the release notes"}
+	translator := &graphql.Object{Name: "Translator", Description: "Someone thanked for localization"}
+	warning := &graphql.Object{Name: "ParseWarning", Description: "A line of the release notes the parser could not interpret"}
+	history := &graphql.Object{Name: "ContributorHistory", Description: "A contributor's contributions across every cached release"}
+	historyRelease := &graphql.Object{Name: "ContributorRelease", Description: "What a contributor was thanked for in one release"}
+
+	query.Fields = []*graphql.Field{
+		{
+			Name: "release", Type: release, Description: "A release by version, e.g. \"1.109\"; a recovery version returns the notes it shipped with",
+			Args: []*graphql.Arg{{Name: "version", Type: nonNull(str)}},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				v, err := scraper.ParseVersion(p.Args["version"].(string))
+				if err != nil {
+					return nil, err
+				}
+				if rel, ok := lookupRelease(queryIndex(p.Context), v); ok {
+					return rel, nil
+				}
+				return nil, nil
+			},
+		},
+		{
+			Name: "releases", Type: list(release), ListSize: 150, Description: "Cached releases, newest first, optionally between two versions",
+			Args: []*graphql.Arg{first,
+				{Name: "from", Type: str, Description: "Oldest version to include"},
+				{Name: "to", Type: str, Description: "Newest version to include"}},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				from, to, err := versionRange(p.Args)
+				if err != nil {
+					return nil, err
+				}
+				var out []scraper.Release
+				for _, rel := range queryIndex(p.Context).Releases() {
+					if (from.IsZero() || !rel.Version.Less(from)) && (to.IsZero() || !to.Less(rel.Version)) {
+						out = append(out, rel)
+					}
+				}
+				return firstN(out, p.Args)
+			},
+		},
+		{
+			Name: "contributor", Type: history, Description: "A contributor by GitHub login; renamed accounts resolve to the same history",
+			Args: []*graphql.Arg{{Name: "login", Type: nonNull(str)}},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				if h := queryIndex(p.Context).Contributor(p.Args["login"].(string)); h != nil {
+					return h, nil
+				}
+				return nil, nil
+			},
+		},
+		{
+			Name: "contributors", Type: list(history), ListSize: 100, Description: "Contributors ranked by pull requests",
+			Args: []*graphql.Arg{{Name: "first", Type: integer, Default: 100, Description: "Return at most this many, up to 100"}, repo},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				var out []*scraper.ContributorHistory
+				for _, h := range queryIndex(p.Context).Contributors() {
+					if h.ReleaseCount > 0 && (p.Args["repo"] == nil || len(historyPRs(h, p.Args)) > 0) {
+						out = append(out, h)
+					}
+				}
+				return firstN(out, p.Args)
+			},
+		},
+	}
+
+	release.Fields = []*graphql.Field{
+		{Name: "version", Type: nonNull(str)},
+		{Name: "displayName", Type: nonNull(str)},
+		{Name: "recovery", Type: str, Description: "The latest recovery release, e.g. \"1.109.2\""},
+		{Name: "date", Type: str, Description: "When the release shipped, RFC 3339"},
+		{Name: "dateMonthOnly", Type: nonNull(boolean), Description: "date is only the month the release is named after"},
+		{
+			Name: "contributors", Type: list(contributor), ListSize: 50, Description: "Pull request contributors",
+			Args: []*graphql.Arg{first, repo},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				var out []scraper.Contributor
+				for _, c := range p.Source.(scraper.Release).Contributors {
+					if p.Args["repo"] == nil || len(filterPRs(c.PRs, p.Args)) > 0 {
+						out = append(out, c)
+					}
+				}
+				return firstN(out, p.Args)
+			},
+		},
+		{
+			Name: "issueTracking", Type: list(contributor), ListSize: 20, Description: "Issue reporters and triagers",
+			Args: []*graphql.Arg{first},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return firstN(p.Source.(scraper.Release).IssueTracking, p.Args)
+			},
+		},
+		{
+			Name: "documentation", Type: list(contributor), ListSize: 10, Description: "Documentation contributors",
+			Args: []*graphql.Arg{first},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return firstN(p.Source.(scraper.Release).Documentation, p.Args)
+			},
+		},
+		{
+			Name: "localization", Type: list(translator), ListSize: 20,
+			Args: []*graphql.Arg{first},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return firstN(p.Source.(scraper.Release).Localization, p.Args)
+			},
+		},
+		{Name: "warnings", Type: list(warning), ListSize: 5},
+	}
+
+	contributor.Fields = []*graphql.Field{
+		{Name: "name", Type: nonNull(str)},
+		{Name: "githubUser", Type: nonNull(str)},
+		{Name: "avatarUrl", Type: nonNull(str)},
+		{
+			Name: "prs", Type: list(pr), ListSize: 3,
+			Args: []*graphql.Arg{first, repo},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return firstN(filterPRs(p.Source.(scraper.Contributor).PRs, p.Args), p.Args)
+			},
+		},
+		{
+			Name: "history", Type: history, Description: "Everything else this contributor was thanked for",
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				if h := queryIndex(p.Context).Contributor(p.Source.(scraper.Contributor).GitHubUser); h != nil {
+					return h, nil
+				}
+				return nil, nil
+			},
+		},
+	}
+
+	pr.Fields = []*graphql.Field{
+		{Name: "title", Type: nonNull(str)},
+		{Name: "url", Type: nonNull(str)},
+		{Name: "repo", Type: nonNull(str), Description: "\"owner/name\""},
+		{Name: "number", Type: nonNull(str)},
+		{Name: "enriched", Type: nonNull(boolean), Description: "Whether the fields below were fetched from the GitHub API"},
+		{Name: "mergedAt", Type: str, Description: "RFC 3339"},
+		{Name: "labels", Type: list(str)},
+		{Name: "additions", Type: nonNull(integer)},
+		{Name: "deletions", Type: nonNull(integer)},
+		{Name: "changedFiles", Type: nonNull(integer)},
+		{Name: "linkedIssues", Type: list(str), Description: "\"owner/repo#number\" of issues the pull request closes"},
+	}
+
+	translator.Fields = []*graphql.Field{
+		{Name: "name", Type: nonNull(str)},
+		{Name: "githubUser", Type: str},
+		{Name: "language", Type: nonNull(str)},
+	}
+
+	warning.Fields = []*graphql.Field{
+		{Name: "line", Type: nonNull(integer), Description: "1-based line number in the notes; 0 for the whole release"},
+		{Name: "text", Type: nonNull(str)},
+		{Name: "reason", Type: nonNull(str)},
+	}
+
+	history.Fields = []*graphql.Field{
+		{Name: "githubUser", Type: nonNull(str)},
+		{Name: "name", Type: nonNull(str)},
+		{Name: "avatarUrl", Type: nonNull(str)},
+		{Name: "totalPRs", Type: nonNull(integer)},
+		{Name: "releaseCount", Type: nonNull(integer), Description: "Releases with pull requests"},
+		{Name: "firstRelease", Type: str},
+		{Name: "latestRelease", Type: str},
+		{Name: "totalDocsPRs", Type: nonNull(integer)},
+		{Name: "issueTrackingReleases", Type: list(str), Description: "Versions thanked for issue tracking, newest first"},
+		{
+			Name: "repos", Type: list(str), Description: "Repositories of the contributor's pull requests, most first",
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				counts := make(map[string]int)
+				var repos []string
+				for _, pr := range historyPRs(p.Source.(*scraper.ContributorHistory), p.Args) {
+					if counts[pr.Repo] == 0 {
+						repos = append(repos, pr.Repo)
+					}
+					counts[pr.Repo]++
+				}
+				sort.SliceStable(repos, func(i, j int) bool { return counts[repos[i]] > counts[repos[j]] })
+				return repos, nil
+			},
+		},
+		{
+			Name: "releases", Type: list(historyRelease), ListSize: 10, Description: "Releases the contributor was thanked in, newest first",
+			Args: []*graphql.Arg{first},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				h := p.Source.(*scraper.ContributorHistory)
+				issues := make(map[scraper.Version]bool, len(h.IssueTrackingReleases))
+				for _, v := range h.IssueTrackingReleases {
+					issues[v] = true
+				}
+				var out []contributorRelease
+				for _, rel := range queryIndex(p.Context).Releases() {
+					v := rel.Version
+					if len(h.PRsByRelease[v]) > 0 || len(h.DocsPRsByRelease[v]) > 0 || issues[v] {
+						out = append(out, contributorRelease{Version: v, PRs: h.PRsByRelease[v], DocsPRs: h.DocsPRsByRelease[v], IssueTracking: issues[v]})
+					}
+				}
+				return firstN(out, p.Args)
+			},
+		},
+		{
+			Name: "prs", Type: list(pr), ListSize: 20, Description: "Pull requests across releases, newest release first",
+			Args: []*graphql.Arg{first, repo},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return firstN(historyPRs(p.Source.(*scraper.ContributorHistory), p.Args), p.Args)
+			},
+		},
+	}
+
+	historyRelease.Fields = []*graphql.Field{
+		{Name: "version", Type: nonNull(str)},
+		{
+			Name: "release", Type: release,
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				if rel, ok := queryIndex(p.Context).Release(p.Source.(contributorRelease).Version); ok {
+					return rel, nil
+				}
+				return nil, nil
+			},
+		},
+		{
+			Name: "prs", Type: list(pr), ListSize: 3,
+			Args: []*graphql.Arg{repo},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				return filterPRs(p.Source.(contributorRelease).PRs, p.Args), nil
+			},
+		},
+		{Name: "docsPRs", Type: list(pr), ListSize: 1},
+		{Name: "issueTracking", Type: nonNull(boolean)},
+	}
+
+	return &graphql.Schema{Query: query, MaxDepth: graphqlMaxDepth, MaxComplexity: graphqlMaxComplexity, MaxFirst: graphqlMaxFirst}
+}
+
+// firstN applies a "first" argument.
+func firstN[T any](items []T, args map[string]interface{}) ([]T, error) {
+	n, ok := args["first"].(int)
+	if !ok {
+		return items, nil
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("first must not be negative")
+	}
+	return items[:min(n, len(items))], nil
+}
+
+// versionRange reads the from and to arguments of releases.
+func versionRange(args map[string]interface{}) (from, to scraper.Version, err error) {
+	if s, ok := args["from"].(string); ok {
+		if from, err = scraper.ParseVersion(s); err != nil {
+			return from, to, err
+		}
+	}
+	if s, ok := args["to"].(string); ok {
+		if to, err = scraper.ParseVersion(s); err != nil {
+			return from, to, err
+		}
+	}
+	return from.Base(), to, nil
+}
+
+// filterPRs applies a "repo" argument.
+func filterPRs(prs []scraper.PR, args map[string]interface{}) []scraper.PR {
+	want, ok := args["repo"].(string)
+	if !ok {
+		return prs
+	}
+	var out []scraper.PR
+	for _, pr := range prs {
+		if pr.InRepo(want) {
+			out = append(out, pr)
+		}
+	}
+	return out
+}
+
+// historyPRs returns h's pull requests, newest release first, filtered by
+// a "repo" argument.
+func historyPRs(h *scraper.ContributorHistory, args map[string]interface{}) []scraper.PR {
+	versions := make([]scraper.Version, 0, len(h.PRsByRelease))
+	for v := range h.PRsByRelease {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[j].Less(versions[i]) })
+	var prs []scraper.PR
+	for _, v := range versions {
+		prs = append(prs, filterPRs(h.PRsByRelease[v], args)...)
+	}
+	return prs
+}
+
+// GraphQLHandler serves GraphQL queries on /api/graphql, as POST with a
+// JSON body or GET with query, operationName and variables parameters. A
+// GET without a query returns the schema.
+func GraphQLHandler(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	switch r.Method {
+	case "GET":
+		q := r.URL.Query()
+		if q.Get("query") == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprint(w, graphqlSchema.SDL())
+			return
+		}
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeGraphQLError(w, "variables must be a JSON object")
+				return
+			}
+		}
+	case "POST":
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBody)).Decode(&req); err != nil {
+			writeGraphQLError(w, "the body must be a JSON object with a query")
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	setCompletenessHeader(w)
+	ctx := context.WithValue(r.Context(), indexKey{}, scraper.GetIndex())
+	resp := graphqlSchema.Execute(ctx, req)
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Data == nil {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeGraphQLError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(graphql.Response{Errors: []*graphql.Error{{Message: message}}})
+}
@@ -0,0 +1,140 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// validRepo matches a repo filter: "owner/name" or just "name".
+var validRepo = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)?$`)
+
+// RepoOption is a repository offered in a repo filter.
+type RepoOption struct {
+	Repo     string
+	PRs      int
+	Selected bool
+}
+
+// repoParam returns the ?repo= filter as the "owner/name" it names in
+// index, as given if index has no such repository, or "" if it is missing
+// or malformed.
+func repoParam(r *http.Request, index *scraper.Index) string {
+	repo := r.URL.Query().Get("repo")
+	if !validRepo.MatchString(repo) {
+		return ""
+	}
+	if s, ok := index.Repo(repo); ok {
+		return s.Repo
+	}
+	return repo
+}
+
+// repoOptions lists the repositories in index for a repo filter, marking
+// selected, as returned by repoParam.
+func repoOptions(index *scraper.Index, selected string) []RepoOption {
+	var options []RepoOption
+	for _, s := range index.Repos() {
+		options = append(options, RepoOption{
+			Repo:     s.Repo,
+			PRs:      s.PRs,
+			Selected: s.Repo == selected,
+		})
+	}
+	return options
+}
+
+// RepoPageData is the view model for a repository page.
+type RepoPageData struct {
+	Repo         scraper.RepoSummary
+	Owner        string
+	Name         string
+	Top          []LeaderboardEntry // by PRs to the repo
+	Docs         []LeaderboardEntry // documentation PRs to the repo, if any
+	Releases     []RepoReleaseView  // releases with PRs to the repo, newest first
+	FirstTimers  int                // contributors who made their first PR to the repo here
+	Completeness scraper.Completeness
+}
+
+// RepoReleaseView is one release on a repository page.
+type RepoReleaseView struct {
+	Version      scraper.Version
+	DisplayName  string
+	Date         string // Release.DateString
+	PRs          int
+	Contributors int
+	FirstTime    []ContributorView // Name, GitHubUser and AvatarURL only
+}
+
+// RepoHandler renders /repo/{owner}/{name}: the top contributors to one
+// repository, its PRs per release and who contributed to it for the first
+// time in each.
+func RepoHandler(w http.ResponseWriter, r *http.Request) {
+	repo := strings.Trim(strings.TrimPrefix(r.URL.Path, "/repo/"), "/")
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || !validRepo.MatchString(repo) {
+		http.NotFound(w, r)
+		return
+	}
+	index := scraper.GetIndex()
+	summary, ok := index.Repo(repo)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	owner, name, _ = strings.Cut(summary.Repo, "/")
+	repoIndex := index.ForRepo(summary.Repo)
+
+	data := RepoPageData{
+		Repo:         summary,
+		Owner:        owner,
+		Name:         name,
+		Top:          leaderboardEntries(repoIndex, "prs"),
+		Docs:         leaderboardEntries(repoIndex, "docs"),
+		Completeness: scraper.GetCompleteness(),
+	}
+	if len(data.Top) > 25 {
+		data.Top = data.Top[:25]
+	}
+	if len(data.Docs) > 25 {
+		data.Docs = data.Docs[:25]
+	}
+
+	// The repo index has every release; list those with PRs to the repo.
+	// First-time contributors count contributors' PRs only, as elsewhere.
+	for _, rel := range repoIndex.Releases() {
+		v := RepoReleaseView{
+			Version:     rel.Version,
+			DisplayName: rel.DisplayName,
+			Date:        rel.DateString(),
+		}
+		seen := make(map[string]bool)
+		for _, c := range slices.Concat(rel.Contributors, rel.Documentation) {
+			v.PRs += len(c.PRs)
+			if key := scraper.CanonicalUser(c.GitHubUser); !seen[key] {
+				seen[key] = true
+				v.Contributors++
+			}
+		}
+		first := make(map[string]bool)
+		for _, c := range rel.Contributors {
+			if key := scraper.CanonicalUser(c.GitHubUser); !first[key] && repoIndex.IsFirstTime(c.GitHubUser, rel.Version) {
+				first[key] = true
+				v.FirstTime = append(v.FirstTime, ContributorView{Name: c.Name, GitHubUser: c.GitHubUser, AvatarURL: c.AvatarURL})
+			}
+		}
+		data.FirstTimers += len(v.FirstTime)
+		if v.PRs > 0 {
+			data.Releases = append(data.Releases, v)
+		}
+	}
+
+	if err := templates.ExecuteTemplate(w, "repo.html", data); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		log.Printf("Template error: %v", err)
+	}
+}
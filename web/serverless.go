@@ -0,0 +1,30 @@
+package web
+
+import (
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/vscode-contributor-website/scraper"
+)
+
+// configureServerless configures the scraper from the environment, which
+// opens the cache directory, once per serverless instance.
+var configureServerless = sync.OnceValue(func() error {
+	return scraper.Configure(scraper.ConfigFromEnv())
+})
+
+// Serverless adapts h for the Vercel functions in api/, which have no main
+// to configure the scraper: the first request configures it from the
+// environment, as main does from the environment and flags, so the
+// functions serve releases from SCRAPER_CACHE_DIR.
+func Serverless(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := configureServerless(); err != nil {
+			log.Printf("Serverless config: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		h(w, r)
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"net/http"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/vscode-contributor-website/heygen"
 	"github.com/vscode-contributor-website/scraper"
@@ -32,20 +34,19 @@ var (
 	validUser  = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9\-]*[a-zA-Z0-9])?$`)
 )
 
-// formatVersion converts "v1_109" to "v1.109"
-func formatVersion(id string) string {
-	// "v1_109" -> "v1.109"
-	s := strings.TrimPrefix(id, "v")
-	s = strings.Replace(s, "_", ".", 1)
-	return "v" + s
-}
-
 // View models
 type ContributorsPageData struct {
 	Versions     []VersionOption
 	Selected     string
+	Recovery     string // latest recovery release of Selected, e.g. "v1.109.2"
+	ReleaseDate  string // when Selected shipped, e.g. "Feb 4, 2026"
 	Contributors []ContributorView
 	Loading      bool
+	Completeness scraper.Completeness
+
+	// Repo, if set, limits the page to PRs to that repository.
+	Repo  string
+	Repos []RepoOption
 }
 
 type VersionOption struct {
@@ -71,6 +72,33 @@ type PRView struct {
 	URL    string
 	Repo   string
 	Number string
+
+	// Only set when the PR has been enriched from the GitHub API.
+	Enriched  bool
+	MergedAt  string // e.g. "Jan 2, 2026"
+	Labels    []string
+	Additions int
+	Deletions int
+}
+
+// newPRView converts a scraper PR into its view model.
+func newPRView(pr scraper.PR) PRView {
+	v := PRView{
+		Title:    pr.Title,
+		URL:      pr.URL,
+		Repo:     pr.Repo,
+		Number:   pr.Number,
+		Enriched: pr.Enriched,
+	}
+	if pr.Enriched {
+		if !pr.MergedAt.IsZero() {
+			v.MergedAt = pr.MergedAt.Format("Jan 2, 2006")
+		}
+		v.Labels = pr.Labels
+		v.Additions = pr.Additions
+		v.Deletions = pr.Deletions
+	}
+	return v
 }
 
 func HomeHandler(w http.ResponseWriter, r *http.Request) {
@@ -107,28 +135,30 @@ func ContributorsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Determine selected version
-	selectedVersion := r.URL.Query().Get("version")
-	if selectedVersion == "" {
+	// Determine selected version; any form ParseVersion accepts will do,
+	// and a recovery release selects the notes it shipped with
+	selectedVersion, _ := scraper.ParseVersion(r.URL.Query().Get("version"))
+	selectedVersion = selectedVersion.Base()
+	if selectedVersion.IsZero() {
 		// Default to the latest version that actually has contributors
 		for _, v := range availableVersions {
-			rel, ok := scraper.GetRelease(v.ID)
+			rel, ok := scraper.GetRelease(v)
 			if ok && len(rel.Contributors) > 0 {
-				selectedVersion = v.ID
+				selectedVersion = v
 				break
 			}
 		}
-		if selectedVersion == "" {
-			selectedVersion = availableVersions[0].ID
+		if selectedVersion.IsZero() {
+			selectedVersion = availableVersions[0]
 		}
 	}
 
 	// Build version options from all available versions
 	for _, v := range availableVersions {
 		data.Versions = append(data.Versions, VersionOption{
-			ID:       v.ID,
-			Display:  v.Display,
-			Selected: v.ID == selectedVersion,
+			ID:       v.ID(),
+			Display:  v.String(),
+			Selected: v == selectedVersion,
 		})
 	}
 
@@ -136,32 +166,43 @@ func ContributorsHandler(w http.ResponseWriter, r *http.Request) {
 	selectedRelease, ok := scraper.GetRelease(selectedVersion)
 	if !ok {
 		// Fallback to first available
-		selectedVersion = availableVersions[0].ID
+		selectedVersion = availableVersions[0]
 		selectedRelease, _ = scraper.GetRelease(selectedVersion)
 		if len(data.Versions) > 0 {
 			for i := range data.Versions {
-				data.Versions[i].Selected = data.Versions[i].ID == selectedVersion
+				data.Versions[i].Selected = data.Versions[i].ID == selectedVersion.ID()
 			}
 		}
 	}
 	data.Selected = selectedRelease.DisplayName
+	data.Recovery = selectedRelease.Recovery.Tag()
+	data.ReleaseDate = selectedRelease.DateString()
+	data.Completeness = scraper.GetCompleteness()
 
-	// Calculate total PR counts across all releases for milestone detection
-	totalPRCounts := make(map[string]int)
-	for _, v := range availableVersions {
-		rel, ok := scraper.GetRelease(v.ID)
-		if !ok {
-			continue
-		}
-		for _, c := range rel.Contributors {
-			totalPRCounts[c.GitHubUser] += len(c.PRs)
-		}
-	}
+	// Total PR counts and first-time flags come from the contributor index
+	index := scraper.GetIndex()
+	data.Repo = repoParam(r, index)
+	data.Repos = repoOptions(index, data.Repo)
 
 	// Build contributor views with kudos counts and milestone info
 	kudosMu.RLock()
 	for _, c := range selectedRelease.Contributors {
-		totalPRs := totalPRCounts[c.GitHubUser]
+		prs := c.PRs
+		if data.Repo != "" {
+			prs = nil
+			for _, pr := range c.PRs {
+				if pr.InRepo(data.Repo) {
+					prs = append(prs, pr)
+				}
+			}
+			if len(prs) == 0 {
+				continue
+			}
+		}
+		totalPRs := len(c.PRs)
+		if h := index.Contributor(c.GitHubUser); h != nil {
+			totalPRs = h.TotalPRs
+		}
 		milestone := 0
 		for _, m := range heygen.Milestones {
 			if totalPRs >= m {
@@ -173,19 +214,14 @@ func ContributorsHandler(w http.ResponseWriter, r *http.Request) {
 			Name:          c.Name,
 			GitHubUser:    c.GitHubUser,
 			AvatarURL:     c.AvatarURL,
-			Kudos:         kudosStore[c.GitHubUser],
+			Kudos:         kudosStore[scraper.CanonicalUser(c.GitHubUser)],
 			TotalPRCount:  totalPRs,
 			Milestone:     milestone,
 			ShowCelebrate: milestone >= 5 && heygenClient.IsConfigured(),
-			IsFirstTime:   scraper.IsFirstTimeContributor(c.GitHubUser, selectedVersion),
-		}
-		for _, pr := range c.PRs {
-			cv.PRs = append(cv.PRs, PRView{
-				Title:  pr.Title,
-				URL:    pr.URL,
-				Repo:   pr.Repo,
-				Number: pr.Number,
-			})
+			IsFirstTime:   index.IsFirstTime(c.GitHubUser, selectedVersion),
+		}
+		for _, pr := range prs {
+			cv.PRs = append(cv.PRs, newPRView(pr))
 		}
 		data.Contributors = append(data.Contributors, cv)
 	}
@@ -197,6 +233,13 @@ func ContributorsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// setCompletenessHeader reports how many releases back a JSON response,
+// e.g. "37/120", so API clients can tell when aggregates are partial.
+func setCompletenessHeader(w http.ResponseWriter) {
+	c := scraper.GetCompleteness()
+	w.Header().Set("X-Data-Completeness", fmt.Sprintf("%d/%d", c.Cached, c.Total))
+}
+
 func KudosHandler(w http.ResponseWriter, r *http.Request) {
 	username := strings.TrimPrefix(r.URL.Path, "/api/kudos/")
 	if username == "" || !validUser.MatchString(username) {
@@ -206,16 +249,19 @@ func KudosHandler(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
+	// Kudos are kept per identity so they survive case changes and renames.
+	key := scraper.CanonicalUser(username)
+
 	switch r.Method {
 	case "POST":
 		kudosMu.Lock()
-		kudosStore[username]++
-		count := kudosStore[username]
+		kudosStore[key]++
+		count := kudosStore[key]
 		kudosMu.Unlock()
 		fmt.Fprintf(w, `{"count":%d}`, count)
 	case "GET":
 		kudosMu.RLock()
-		count := kudosStore[username]
+		count := kudosStore[key]
 		kudosMu.RUnlock()
 		fmt.Fprintf(w, `{"count":%d}`, count)
 	default:
@@ -334,24 +380,15 @@ func CheckMilestone(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	setCompletenessHeader(w)
 
 	// Get contributor's PR count across all versions
-	allVersions := scraper.GetAvailableVersions()
 	prCount := 0
 	contributorName := username
-
-	for _, v := range allVersions {
-		rel, ok := scraper.GetRelease(v.ID)
-		if !ok {
-			continue
-		}
-		for _, c := range rel.Contributors {
-			if strings.EqualFold(c.GitHubUser, username) {
-				prCount += len(c.PRs)
-				if c.Name != "" {
-					contributorName = c.Name
-				}
-			}
+	if h := scraper.GetIndex().Contributor(username); h != nil {
+		prCount = h.TotalPRs
+		if h.Name != "" {
+			contributorName = h.Name
 		}
 	}
 
@@ -370,17 +407,23 @@ func CheckMilestone(w http.ResponseWriter, r *http.Request) {
 		"milestone":        milestone,
 		"is_milestone":     heygen.IsMilestone(prCount),
 		"configured":       heygenClient.IsConfigured(),
+		"data_complete":    scraper.GetCompleteness().Complete(),
 	})
 }
 
 // Leaderboard data types
 type LeaderboardEntry struct {
-	Rank       int
-	Name       string
-	GitHubUser string
-	AvatarURL  string
-	PRCount    int
-	Releases   int
+	Rank       int    `json:"rank"`
+	Name       string `json:"name"`
+	GitHubUser string `json:"github_user,omitempty"` // empty for translators known only by name
+	AvatarURL  string `json:"avatar_url,omitempty"`
+	PRCount    int    `json:"pr_count"`
+	Releases   int    `json:"releases"`
+
+	// Set on the non-PR tabs.
+	IssueReleases int    `json:"issue_releases,omitempty"` // releases thanked for issue tracking
+	DocsPRs       int    `json:"docs_prs,omitempty"`       // documentation PRs
+	Languages     string `json:"languages,omitempty"`      // translated languages, comma separated
 }
 
 // ContributorProfileData is the view model for the contributor profile page.
@@ -390,25 +433,90 @@ type ContributorProfileData struct {
 	AvatarURL            string
 	TotalPRs             int
 	ReleaseCount         int
-	FirstRelease         string
-	LatestRelease        string
+	FirstRelease         scraper.Version
+	LatestRelease        scraper.Version
 	FirstReleaseDisplay  string
 	LatestReleaseDisplay string
+	FirstReleaseDate     string // when FirstRelease shipped, e.g. "May 2019"
+	LatestReleaseDate    string
+	PRsPastYear          int // PRs in releases shipped in the last 12 months
 	Releases             []ProfileRelease
 	Kudos                int
+	Completeness         scraper.Completeness
+	HasEnrichment        bool // whether any PR carries GitHub API metadata
+	LinesChanged         int  // additions + deletions across enriched PRs
+
+	// Tab is "prs", "docs" or "issues"; each has its own list of releases.
+	Tab               string
+	TotalDocsPRs      int
+	IssueReleaseCount int
+	DocsReleases      []ProfileRelease
+	IssueReleases     []ProfileRelease // PRs is always empty
+	Shown             []ProfileRelease // Releases or DocsReleases, per Tab
 }
 
 // ProfileRelease holds PRs for a release on the profile page.
 type ProfileRelease struct {
-	Version     string
+	Version     scraper.Version
 	DisplayName string
+	Date        string // Release.DateString
 	PRs         []PRView
 }
 
 type LeaderboardPageData struct {
-	Tab     string // "prs" or "releases"
-	Entries []LeaderboardEntry
-	Loading bool
+	Tab          string // "prs", "releases", "issues", "docs" or "translations"
+	Entries      []LeaderboardEntry
+	Loading      bool
+	Completeness scraper.Completeness
+
+	// From and To are the inclusive date range ("2006-01-02"), either may
+	// be empty; RangeReleases counts the releases that shipped in it.
+	From          string
+	To            string
+	Ranges        []RangeOption
+	RangeReleases int
+
+	// Repo, if set, limits the rankings to PRs to that repository.
+	Repo  string
+	Repos []RepoOption
+}
+
+// RangeOption is a preset date range offered on the leaderboard.
+type RangeOption struct {
+	Label    string
+	From     string
+	To       string
+	Selected bool
+}
+
+// dateLayout is the form of the leaderboard's from and to parameters.
+const dateLayout = "2006-01-02"
+
+// leaderboardRanges offers all time, the past year and the most recent
+// calendar years that have dated releases.
+func leaderboardRanges(index *scraper.Index, from, to string) []RangeOption {
+	now := time.Now()
+	ranges := []RangeOption{
+		{Label: "All time"},
+		{Label: "Past 12 months", From: now.AddDate(-1, 0, 0).Format(dateLayout)},
+	}
+	seen := make(map[int]bool)
+	for _, rel := range index.Releases() {
+		year := rel.Date.Year()
+		if rel.Date.IsZero() || seen[year] || len(seen) == 4 {
+			continue
+		}
+		seen[year] = true
+		ranges = append(ranges, RangeOption{
+			Label: fmt.Sprint(year),
+			From:  fmt.Sprintf("%d-01-01", year),
+			To:    fmt.Sprintf("%d-12-31", year),
+		})
+	}
+	for i := range ranges {
+		ranges[i].Selected = ranges[i].From == from && ranges[i].To == to
+	}
+	return ranges
 }
 
 func ContributorProfileHandler(w http.ResponseWriter, r *http.Request) {
@@ -434,36 +542,97 @@ func ContributorProfileHandler(w http.ResponseWriter, r *http.Request) {
 		ReleaseCount:         history.ReleaseCount,
 		FirstRelease:         history.FirstRelease,
 		LatestRelease:        history.LatestRelease,
-		FirstReleaseDisplay:  formatVersion(history.FirstRelease),
-		LatestReleaseDisplay: formatVersion(history.LatestRelease),
+		FirstReleaseDisplay:  "—",
+		LatestReleaseDisplay: "—",
+		TotalDocsPRs:         history.TotalDocsPRs,
+		IssueReleaseCount:    len(history.IssueTrackingReleases),
+	}
+	index := scraper.GetIndex()
+	if history.ReleaseCount > 0 {
+		data.FirstReleaseDisplay = history.FirstRelease.Tag()
+		data.LatestReleaseDisplay = history.LatestRelease.Tag()
+		if rel, ok := index.Release(history.FirstRelease); ok {
+			data.FirstReleaseDate = rel.DateString()
+		}
+		if rel, ok := index.Release(history.LatestRelease); ok {
+			data.LatestReleaseDate = rel.DateString()
+		}
 	}
+	pastYear := time.Now().AddDate(-1, 0, 0)
 
-	// Convert releases from PRsByRelease map
-	versions := scraper.GetAvailableVersions()
+	// Convert releases from PRsByRelease map, in the order they shipped
+	var versions []scraper.Version
+	for _, rel := range index.Releases() {
+		versions = append(versions, rel.Version)
+	}
+	releaseDate := func(v scraper.Version) (time.Time, string) {
+		rel, _ := index.Release(v)
+		return rel.Date, rel.DateString()
+	}
 	for _, v := range versions {
-		prs, ok := history.PRsByRelease[v.ID]
+		prs, ok := history.PRsByRelease[v]
 		if !ok || len(prs) == 0 {
 			continue
 		}
+		date, dateString := releaseDate(v)
+		if date.After(pastYear) {
+			data.PRsPastYear += len(prs)
+		}
 		pr := ProfileRelease{
-			Version:     v.ID,
-			DisplayName: v.Display,
+			Version:     v,
+			DisplayName: v.String(),
+			Date:        dateString,
 		}
 		for _, p := range prs {
-			pr.PRs = append(pr.PRs, PRView{
-				Title:  p.Title,
-				URL:    p.URL,
-				Repo:   p.Repo,
-				Number: p.Number,
-			})
+			pr.PRs = append(pr.PRs, newPRView(p))
+			if p.Enriched {
+				data.HasEnrichment = true
+				data.LinesChanged += p.Additions + p.Deletions
+			}
 		}
 		data.Releases = append(data.Releases, pr)
 	}
+	issueReleases := make(map[scraper.Version]bool, len(history.IssueTrackingReleases))
+	for _, v := range history.IssueTrackingReleases {
+		issueReleases[v] = true
+	}
+	for _, v := range versions {
+		_, dateString := releaseDate(v)
+		if prs := history.DocsPRsByRelease[v]; len(prs) > 0 {
+			pr := ProfileRelease{Version: v, DisplayName: v.String(), Date: dateString}
+			for _, p := range prs {
+				pr.PRs = append(pr.PRs, newPRView(p))
+			}
+			data.DocsReleases = append(data.DocsReleases, pr)
+		}
+		if issueReleases[v] {
+			data.IssueReleases = append(data.IssueReleases, ProfileRelease{Version: v, DisplayName: v.String(), Date: dateString})
+		}
+	}
+
+	// Default to the first tab that has anything to show
+	data.Tab = r.URL.Query().Get("tab")
+	switch {
+	case data.Tab == "prs" || data.Tab == "docs" || data.Tab == "issues":
+	case len(data.Releases) == 0 && len(data.DocsReleases) > 0:
+		data.Tab = "docs"
+	case len(data.Releases) == 0 && len(data.IssueReleases) > 0:
+		data.Tab = "issues"
+	default:
+		data.Tab = "prs"
+	}
+	switch data.Tab {
+	case "prs":
+		data.Shown = data.Releases
+	case "docs":
+		data.Shown = data.DocsReleases
+	}
 
 	// Get kudos count
 	kudosMu.RLock()
-	data.Kudos = kudosStore[history.GitHubUser]
+	data.Kudos = kudosStore[scraper.CanonicalUser(history.GitHubUser)]
 	kudosMu.RUnlock()
+	data.Completeness = scraper.GetCompleteness()
 
 	if err := templates.ExecuteTemplate(w, "contributor.html", data); err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -483,95 +652,126 @@ func LeaderboardHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tab := r.URL.Query().Get("tab")
-	if tab != "releases" {
+	switch tab {
+	case "releases", "issues", "docs", "translations":
+	default:
 		tab = "prs"
 	}
 
-	// Aggregate across all cached releases
-	type userStats struct {
-		Name       string
-		GitHubUser string
-		AvatarURL  string
-		PRCount    int
-		Releases   map[string]bool
+	// Optional date range; to is inclusive, so the index is cut a day later
+	var from, to time.Time
+	fromParam, toParam := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+	if t, err := time.Parse(dateLayout, fromParam); err == nil {
+		from = t
+	} else {
+		fromParam = ""
+	}
+	if t, err := time.Parse(dateLayout, toParam); err == nil {
+		to = t.AddDate(0, 0, 1)
+	} else {
+		toParam = ""
 	}
 
-	statsMap := make(map[string]*userStats)
+	// Aggregates come precomputed from the contributor index
+	allTime := scraper.GetIndex()
+	repo := repoParam(r, allTime)
+	index := allTime.Between(from, to).ForRepo(repo)
 
-	for _, v := range availableVersions {
-		rel, ok := scraper.GetRelease(v.ID)
-		if !ok || len(rel.Contributors) == 0 {
-			continue
-		}
-		for _, c := range rel.Contributors {
-			s, exists := statsMap[c.GitHubUser]
-			if !exists {
-				s = &userStats{
-					Name:       c.Name,
-					GitHubUser: c.GitHubUser,
-					AvatarURL:  c.AvatarURL,
-					Releases:   make(map[string]bool),
-				}
-				statsMap[c.GitHubUser] = s
-			}
-			s.PRCount += len(c.PRs)
-			s.Releases[v.ID] = true
-			// Keep the most recent name/avatar
-			if c.Name != "" {
-				s.Name = c.Name
-			}
-			if c.AvatarURL != "" {
-				s.AvatarURL = c.AvatarURL
-			}
-		}
+	// Limit to top 50
+	entries := leaderboardEntries(index, tab)
+	if len(entries) > 50 {
+		entries = entries[:50]
 	}
 
-	entries := make([]LeaderboardEntry, 0, len(statsMap))
-	for _, s := range statsMap {
-		entries = append(entries, LeaderboardEntry{
-			Name:       s.Name,
-			GitHubUser: s.GitHubUser,
-			AvatarURL:  s.AvatarURL,
-			PRCount:    s.PRCount,
-			Releases:   len(s.Releases),
-		})
+	data := LeaderboardPageData{
+		Tab:           tab,
+		Entries:       entries,
+		Completeness:  scraper.GetCompleteness(),
+		From:          fromParam,
+		To:            toParam,
+		Ranges:        leaderboardRanges(allTime, fromParam, toParam),
+		RangeReleases: len(index.Releases()),
+		Repo:          repo,
+		Repos:         repoOptions(allTime, repo),
 	}
 
-	if tab == "releases" {
-		sort.Slice(entries, func(i, j int) bool {
-			if entries[i].Releases != entries[j].Releases {
-				return entries[i].Releases > entries[j].Releases
+	if err := templates.ExecuteTemplate(w, "leaderboard.html", data); err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		log.Printf("Template error: %v", err)
+	}
+}
+
+// leaderboardEntries ranks everyone in index for a leaderboard tab: "prs",
+// "releases", "issues", "docs" or "translations".
+func leaderboardEntries(index *scraper.Index, tab string) []LeaderboardEntry {
+	var entries []LeaderboardEntry
+	switch tab {
+	case "issues":
+		for _, h := range index.IssueTrackers() {
+			entries = append(entries, LeaderboardEntry{
+				Name:          h.Name,
+				GitHubUser:    h.GitHubUser,
+				AvatarURL:     h.AvatarURL,
+				IssueReleases: len(h.IssueTrackingReleases),
+			})
+		}
+	case "docs":
+		for _, h := range index.DocsContributors() {
+			entries = append(entries, LeaderboardEntry{
+				Name:       h.Name,
+				GitHubUser: h.GitHubUser,
+				AvatarURL:  h.AvatarURL,
+				DocsPRs:    h.TotalDocsPRs,
+			})
+		}
+	case "translations":
+		for _, t := range index.Translators() {
+			e := LeaderboardEntry{
+				Name:       t.Name,
+				GitHubUser: t.GitHubUser,
+				Releases:   t.ReleaseCount,
+				Languages:  strings.Join(t.Languages, ", "),
 			}
-			return entries[i].PRCount > entries[j].PRCount
-		})
-	} else {
-		sort.Slice(entries, func(i, j int) bool {
-			if entries[i].PRCount != entries[j].PRCount {
-				return entries[i].PRCount > entries[j].PRCount
+			if t.GitHubUser != "" {
+				e.AvatarURL = fmt.Sprintf("https://github.com/%s.png?size=80", t.GitHubUser)
 			}
-			return entries[i].Releases > entries[j].Releases
-		})
+			entries = append(entries, e)
+		}
+	default:
+		for _, h := range index.Contributors() {
+			if h.ReleaseCount == 0 {
+				// Thanked only for issue tracking or documentation.
+				continue
+			}
+			entries = append(entries, LeaderboardEntry{
+				Name:       h.Name,
+				GitHubUser: h.GitHubUser,
+				AvatarURL:  h.AvatarURL,
+				PRCount:    h.TotalPRs,
+				Releases:   h.ReleaseCount,
+			})
+		}
+		if tab == "releases" {
+			sort.Slice(entries, func(i, j int) bool {
+				if entries[i].Releases != entries[j].Releases {
+					return entries[i].Releases > entries[j].Releases
+				}
+				return entries[i].PRCount > entries[j].PRCount
+			})
+		} else {
+			sort.Slice(entries, func(i, j int) bool {
+				if entries[i].PRCount != entries[j].PRCount {
+					return entries[i].PRCount > entries[j].PRCount
+				}
+				return entries[i].Releases > entries[j].Releases
+			})
+		}
 	}
 
-	// Assign ranks and limit to top 50
-	limit := 50
-	if limit > len(entries) {
-		limit = len(entries)
-	}
-	entries = entries[:limit]
 	for i := range entries {
 		entries[i].Rank = i + 1
 	}
-
-	data := LeaderboardPageData{
-		Tab:     tab,
-		Entries: entries,
-	}
-
-	if err := templates.ExecuteTemplate(w, "leaderboard.html", data); err != nil {
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-		log.Printf("Template error: %v", err)
-	}
+	return entries
 }
 
 // Search data types
@@ -614,6 +814,7 @@ func SearchHandler(w http.ResponseWriter, r *http.Request) {
 func SearchAPIHandler(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	w.Header().Set("Content-Type", "application/json")
+	setCompletenessHeader(w)
 
 	if query == "" {
 		json.NewEncoder(w).Encode([]SearchResult{})
@@ -634,3 +835,172 @@ func SearchAPIHandler(w http.ResponseWriter, r *http.Request) {
 
 	json.NewEncoder(w).Encode(apiResults)
 }
+
+// ParseReportResult is one release's entry in the /api/parse-report response.
+type ParseReportResult struct {
+	Version       scraper.Version        `json:"version"`
+	Contributors  int                    `json:"contributors"`
+	PRs           int                    `json:"prs"`
+	IssueTracking int                    `json:"issue_tracking"`
+	Documentation int                    `json:"documentation"`
+	Localization  int                    `json:"localization"`
+	Incomplete    bool                   `json:"incomplete"`
+	Warnings      []scraper.ParseWarning `json:"warnings"`
+}
+
+// ParseReportHandler lists, per cached release, how many contributors and
+// PRs were parsed and which lines were skipped. ?incomplete=1 limits the
+// list to releases with warnings.
+func ParseReportHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	setCompletenessHeader(w)
+	onlyIncomplete := r.URL.Query().Get("incomplete") != ""
+
+	results := []ParseReportResult{}
+	for _, rep := range scraper.GetParseReports() {
+		if onlyIncomplete && !rep.Incomplete() {
+			continue
+		}
+		results = append(results, ParseReportResult{
+			Version:       rep.Version,
+			Contributors:  rep.Contributors,
+			PRs:           rep.PRs,
+			IssueTracking: rep.IssueTracking,
+			Documentation: rep.Documentation,
+			Localization:  rep.Localization,
+			Incomplete:    rep.Incomplete(),
+			Warnings:      rep.Warnings,
+		})
+	}
+	json.NewEncoder(w).Encode(results)
+}
+
+// StatusResult is the /api/status response. Times are RFC 3339, or empty
+// when the event has not happened yet.
+type StatusResult struct {
+	Ready         bool               `json:"ready"`
+	Stale         bool               `json:"stale"` // no successful refresh for two refresh intervals
+	LastRefresh   string             `json:"last_refresh,omitempty"`
+	LastAttempt   string             `json:"last_attempt,omitempty"`
+	LastError     string             `json:"last_error,omitempty"`
+	LastErrorAt   string             `json:"last_error_at,omitempty"`
+	UsingFallback bool               `json:"using_fallback"`
+	Discovered    int                `json:"discovered"`
+	Cached        int                `json:"cached"`
+	Backfilling   bool               `json:"backfilling"`
+	FetchErrors   []FetchErrorResult `json:"fetch_errors"`
+	RateLimit     RateLimitResult    `json:"rate_limit"`
+}
+
+// FetchErrorResult is a discovered version that failed to fetch.
+type FetchErrorResult struct {
+	Version scraper.Version `json:"version"`
+	Error   string          `json:"error"`
+	At      string          `json:"at"`
+}
+
+// RateLimitResult is the GitHub API rate-limit state; Remaining is -1
+// until the first API response is seen.
+type RateLimitResult struct {
+	Authenticated bool   `json:"authenticated"`
+	Limit         int    `json:"limit"`
+	Remaining     int    `json:"remaining"`
+	Reset         string `json:"reset,omitempty"`
+	Exhausted     bool   `json:"exhausted"`
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// StatusHandler reports the scraper's health: when it last refreshed, the
+// last error, how many versions are discovered and cached, which versions
+// fail to fetch, whether fallback versions are in use and the rate limit.
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	setCompletenessHeader(w)
+
+	s := scraper.GetStatus()
+	result := StatusResult{
+		Ready:         s.Ready,
+		Stale:         s.Stale(),
+		LastRefresh:   formatTime(s.LastRefresh),
+		LastAttempt:   formatTime(s.LastAttempt),
+		LastError:     s.LastError,
+		LastErrorAt:   formatTime(s.LastErrorAt),
+		UsingFallback: s.UsingFallback,
+		Discovered:    s.Discovered,
+		Cached:        s.Cached,
+		Backfilling:   s.Completeness.Backfilling,
+		FetchErrors:   []FetchErrorResult{},
+		RateLimit: RateLimitResult{
+			Authenticated: s.RateLimit.Authenticated,
+			Limit:         s.RateLimit.Limit,
+			Remaining:     s.RateLimit.Remaining,
+			Reset:         formatTime(s.RateLimit.Reset),
+			Exhausted:     s.RateLimit.Exhausted(),
+		},
+	}
+	for _, e := range s.FetchErrors {
+		result.FetchErrors = append(result.FetchErrors, FetchErrorResult{
+			Version: e.Version,
+			Error:   e.Error,
+			At:      formatTime(e.At),
+		})
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// HealthzHandler is the liveness probe: the process is up and serving.
+func HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// ReadyzHandler is the readiness probe. It fails with 503 until a refresh
+// has discovered versions or cached releases, so traffic isn't routed to an
+// instance that would only show the loading page.
+func ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !scraper.IsReady() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "not ready: no versions or releases yet")
+		return
+	}
+	fmt.Fprintln(w, "ready")
+}
+
+// ChangesHandler is the feed of changes between refreshes, newest first:
+// contributors and PRs added to or removed from a release when its notes
+// were edited, and newly published releases. ?since=<Seq> returns only
+// later changes, for polling; ?version=1.109 limits the feed to a release.
+func ChangesHandler(w http.ResponseWriter, r *http.Request) {
+	var since uint64
+	if s := r.URL.Query().Get("since"); s != "" {
+		var err error
+		if since, err = strconv.ParseUint(s, 10, 64); err != nil {
+			http.Error(w, "Invalid since", http.StatusBadRequest)
+			return
+		}
+	}
+	var version scraper.Version
+	if v := r.URL.Query().Get("version"); v != "" {
+		var err error
+		if version, err = scraper.ParseVersion(v); err != nil {
+			http.Error(w, "Invalid version", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	changes := []scraper.Change{}
+	for _, c := range scraper.GetChanges(since) {
+		if version.IsZero() || c.Version == version.Base() {
+			changes = append(changes, c)
+		}
+	}
+	json.NewEncoder(w).Encode(changes)
+}